@@ -0,0 +1,3 @@
+module receipt-processor
+
+go 1.22
@@ -0,0 +1,45 @@
+package handlers
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"net/http"
+)
+
+// APIKeyHeader carries the client's API key.
+const APIKeyHeader = "X-API-Key"
+
+// APIKeyMiddleware rejects requests with 401 unless APIKeyHeader holds one
+// of keys. Keys are compared in constant time. With no keys configured,
+// authentication is disabled and every request passes through.
+func APIKeyMiddleware(keys []string) func(http.Handler) http.Handler {
+	digests := make([][sha256.Size]byte, len(keys))
+	for i, k := range keys {
+		digests[i] = sha256.Sum256([]byte(k))
+	}
+	return func(next http.Handler) http.Handler {
+		if len(digests) == 0 {
+			return next
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			presented := r.Header.Get(APIKeyHeader)
+			if presented == "" || !matchesKey(digests, presented) {
+				writeError(w, http.StatusUnauthorized, "A valid API key is required.")
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// matchesKey compares key against every configured digest without
+// short-circuiting. Hashing first keeps the comparison independent of the
+// presented key's length.
+func matchesKey(digests [][sha256.Size]byte, key string) bool {
+	sum := sha256.Sum256([]byte(key))
+	match := 0
+	for i := range digests {
+		match |= subtle.ConstantTimeCompare(sum[:], digests[i][:])
+	}
+	return match == 1
+}
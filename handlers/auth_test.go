@@ -0,0 +1,48 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+var okHandler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusOK)
+})
+
+func TestAPIKeyMiddleware(t *testing.T) {
+	h := APIKeyMiddleware([]string{"secret-1", "secret-2"})(okHandler)
+
+	tests := []struct {
+		name   string
+		key    string
+		status int
+	}{
+		{"valid key", "secret-2", http.StatusOK},
+		{"missing key", "", http.StatusUnauthorized},
+		{"wrong key", "secret-3", http.StatusUnauthorized},
+		{"prefix of a key", "secret", http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.key != "" {
+				req.Header.Set(APIKeyHeader, tt.key)
+			}
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+			if rec.Code != tt.status {
+				t.Errorf("status = %d, want %d", rec.Code, tt.status)
+			}
+		})
+	}
+}
+
+func TestAPIKeyMiddlewareDisabled(t *testing.T) {
+	rec := httptest.NewRecorder()
+	APIKeyMiddleware(nil)(okHandler).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	if rec.Code != http.StatusOK {
+		t.Errorf("status = %d, want 200 with auth disabled", rec.Code)
+	}
+}
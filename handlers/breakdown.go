@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"net/http"
+
+	"receipt-processor/models"
+	"receipt-processor/services"
+)
+
+// breakdownResponse is the JSON body returned by the breakdown handlers.
+type breakdownResponse struct {
+	Total     int                   `json:"total"`
+	Breakdown []models.Contribution `json:"breakdown"`
+}
+
+// BreakdownHandler serves GET /receipts/{id}/breakdown: the per-rule
+// breakdown of a stored receipt under p.Rules. IDs that p.ValidateID
+// rejects get 400 and unknown IDs 404.
+func BreakdownHandler(p *services.Processor) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id := r.PathValue("id")
+		if err := p.ValidateID(id); err != nil {
+			writeServiceError(w, err)
+			return
+		}
+		entry, err := p.Store.Get(id)
+		if err != nil {
+			writeServiceError(w, err)
+			return
+		}
+		writeBreakdown(w, entry.Receipt, p.Rules)
+	}
+}
+
+// SubmittedBreakdownHandler serves POST /receipts/breakdown: the per-rule
+// breakdown of the points the receipt in the request body would earn if
+// processed by p now. Nothing is stored.
+func SubmittedBreakdownHandler(p *services.Processor) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		receipt, err := decodeReceipt(r)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		total, breakdown, err := p.Quote(receipt)
+		if err != nil {
+			writeServiceError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, breakdownResponse{Total: total, Breakdown: breakdown})
+	}
+}
+
+func writeBreakdown(w http.ResponseWriter, receipt models.Receipt, cfg models.RuleConfig) {
+	total, breakdown, err := services.CalculatePointsWithBreakdown(receipt, cfg)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, breakdownResponse{Total: total, Breakdown: breakdown})
+}
@@ -0,0 +1,93 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"receipt-processor/services"
+)
+
+func assertBreakdownSums(t *testing.T, resp breakdownResponse, want int) {
+	t.Helper()
+	if resp.Total != want {
+		t.Errorf("total = %d, want %d", resp.Total, want)
+	}
+	if len(resp.Breakdown) == 0 {
+		t.Fatal("empty breakdown")
+	}
+	sum := 0
+	for _, c := range resp.Breakdown {
+		if c.Rule == "" {
+			t.Errorf("contribution without rule name: %+v", c)
+		}
+		sum += c.Points
+	}
+	if sum != resp.Total {
+		t.Errorf("breakdown sums to %d, total is %d", sum, resp.Total)
+	}
+}
+
+func TestBreakdownHandlerStoredReceipt(t *testing.T) {
+	store := services.NewMapStore()
+	id, err := services.ProcessReceipt(targetReceipt(), store)
+	if err != nil {
+		t.Fatalf("ProcessReceipt: %v", err)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/receipts/"+id+"/breakdown", nil)
+	req.SetPathValue("id", id)
+	rec := httptest.NewRecorder()
+	BreakdownHandler(services.NewProcessor(store))(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", rec.Code)
+	}
+	var resp breakdownResponse
+	decodeBody(t, rec, &resp)
+	assertBreakdownSums(t, resp, 28)
+}
+
+func TestBreakdownHandlerUnknownID(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/receipts/missing/breakdown", nil)
+	req.SetPathValue("id", "missing")
+	rec := httptest.NewRecorder()
+	BreakdownHandler(services.NewProcessor(services.NewMapStore()))(rec, req)
+
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("status = %d, want 404", rec.Code)
+	}
+}
+
+func TestSubmittedBreakdownHandler(t *testing.T) {
+	rec := httptest.NewRecorder()
+	req := jsonRequest(t, http.MethodPost, "/receipts/breakdown", targetReceipt())
+	SubmittedBreakdownHandler(services.NewProcessor(services.NewMapStore()))(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200: %s", rec.Code, rec.Body)
+	}
+	var resp breakdownResponse
+	decodeBody(t, rec, &resp)
+	assertBreakdownSums(t, resp, 28)
+}
+
+func TestSubmittedBreakdownHandlerInvalidReceipt(t *testing.T) {
+	receipt := targetReceipt()
+	receipt.Total = "35"
+	rec := httptest.NewRecorder()
+	SubmittedBreakdownHandler(services.NewProcessor(services.NewMapStore()))(rec, jsonRequest(t, http.MethodPost, "/receipts/breakdown", receipt))
+
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want 400", rec.Code)
+	}
+}
+
+func TestBreakdownHandlerStrictIDs(t *testing.T) {
+	p := services.NewProcessor(services.NewMapStore())
+	p.StrictIDs = true
+	rec := getWithID(BreakdownHandler(p), "/receipts/missing/breakdown", "missing")
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want 400", rec.Code)
+	}
+}
@@ -0,0 +1,17 @@
+package handlers
+
+import (
+	"net/http"
+
+	"receipt-processor/models"
+)
+
+// ConfigHandler serves GET /config: the rule config points are calculated
+// with, in the JSON form LoadRuleConfig reads. RuleGates are code and are
+// left out.
+func ConfigHandler(cfg models.RuleConfig) http.HandlerFunc {
+	cfg.SchemaVersion = models.RuleConfigSchemaVersion
+	return func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, cfg)
+	}
+}
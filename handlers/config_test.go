@@ -0,0 +1,32 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"receipt-processor/models"
+	"receipt-processor/services"
+)
+
+func TestConfigHandler(t *testing.T) {
+	cfg := models.DefaultRuleConfig()
+	cfg.Version = "summer-2024"
+	cfg.OddDayPoints = 12
+	cfg.TimeWindows = []models.TimeWindow{{Start: "09:00", End: "10:00", Points: 4}}
+	cfg.RuleGates = map[string]models.ReceiptPredicate{"time_window": services.MinTotal(500, cfg.DecimalSeparator)}
+
+	rec := httptest.NewRecorder()
+	ConfigHandler(cfg)(rec, httptest.NewRequest(http.MethodGet, "/config", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", rec.Code)
+	}
+	var got models.RuleConfig
+	decodeBody(t, rec, &got)
+	cfg.RuleGates = nil
+	if !reflect.DeepEqual(got, cfg) {
+		t.Errorf("config = %+v, want %+v", got, cfg)
+	}
+}
@@ -0,0 +1,57 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+)
+
+// CORSConfig lists what cross-origin browser clients may do. An origin of
+// "*" allows any origin.
+type CORSConfig struct {
+	AllowedOrigins []string
+	AllowedMethods []string
+	AllowedHeaders []string
+}
+
+// CORSMiddleware answers preflight requests from allowed origins with 204
+// and the configured headers, and adds Access-Control-Allow-Origin to their
+// other responses. Requests from other origins pass through without CORS
+// headers. With no allowed origins, CORS is disabled.
+func CORSMiddleware(cfg CORSConfig) func(http.Handler) http.Handler {
+	methods := strings.Join(cfg.AllowedMethods, ", ")
+	headers := strings.Join(cfg.AllowedHeaders, ", ")
+	return func(next http.Handler) http.Handler {
+		if len(cfg.AllowedOrigins) == 0 {
+			return next
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Add("Vary", "Origin")
+			origin := r.Header.Get("Origin")
+			if origin == "" || !originAllowed(cfg.AllowedOrigins, origin) {
+				next.ServeHTTP(w, r)
+				return
+			}
+			w.Header().Set("Access-Control-Allow-Origin", origin)
+			if r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != "" {
+				if methods != "" {
+					w.Header().Set("Access-Control-Allow-Methods", methods)
+				}
+				if headers != "" {
+					w.Header().Set("Access-Control-Allow-Headers", headers)
+				}
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func originAllowed(allowed []string, origin string) bool {
+	for _, o := range allowed {
+		if o == "*" || o == origin {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+var testCORS = CORSConfig{
+	AllowedOrigins: []string{"https://app.example.com"},
+	AllowedMethods: []string{http.MethodGet, http.MethodPost},
+	AllowedHeaders: []string{"Content-Type", APIKeyHeader},
+}
+
+func corsRequest(method, origin string) *http.Request {
+	req := httptest.NewRequest(method, "/receipts/process", nil)
+	req.Header.Set("Origin", origin)
+	if method == http.MethodOptions {
+		req.Header.Set("Access-Control-Request-Method", http.MethodPost)
+	}
+	return req
+}
+
+func TestCORSPreflight(t *testing.T) {
+	rec := httptest.NewRecorder()
+	CORSMiddleware(testCORS)(okHandler).ServeHTTP(rec, corsRequest(http.MethodOptions, "https://app.example.com"))
+
+	if rec.Code != http.StatusNoContent {
+		t.Errorf("status = %d, want 204", rec.Code)
+	}
+	want := map[string]string{
+		"Access-Control-Allow-Origin":  "https://app.example.com",
+		"Access-Control-Allow-Methods": "GET, POST",
+		"Access-Control-Allow-Headers": "Content-Type, X-API-Key",
+	}
+	for header, value := range want {
+		if got := rec.Header().Get(header); got != value {
+			t.Errorf("%s = %q, want %q", header, got, value)
+		}
+	}
+}
+
+func TestCORSSimpleRequest(t *testing.T) {
+	rec := httptest.NewRecorder()
+	CORSMiddleware(testCORS)(okHandler).ServeHTTP(rec, corsRequest(http.MethodPost, "https://app.example.com"))
+
+	if rec.Code != http.StatusOK {
+		t.Errorf("status = %d, want 200 from the wrapped handler", rec.Code)
+	}
+	if got := rec.Header().Get("Access-Control-Allow-Origin"); got != "https://app.example.com" {
+		t.Errorf("Access-Control-Allow-Origin = %q", got)
+	}
+}
+
+func TestCORSDisallowedOrigin(t *testing.T) {
+	for _, method := range []string{http.MethodOptions, http.MethodPost} {
+		rec := httptest.NewRecorder()
+		CORSMiddleware(testCORS)(okHandler).ServeHTTP(rec, corsRequest(method, "https://evil.example.com"))
+		for _, header := range []string{"Access-Control-Allow-Origin", "Access-Control-Allow-Methods"} {
+			if got := rec.Header().Get(header); got != "" {
+				t.Errorf("%s: %s = %q, want none", method, header, got)
+			}
+		}
+	}
+}
+
+func TestCORSDisabledByDefault(t *testing.T) {
+	rec := httptest.NewRecorder()
+	CORSMiddleware(CORSConfig{})(okHandler).ServeHTTP(rec, corsRequest(http.MethodOptions, "https://app.example.com"))
+	if rec.Code != http.StatusOK || rec.Header().Get("Access-Control-Allow-Origin") != "" {
+		t.Errorf("status %d, headers %v: want the request passed through untouched", rec.Code, rec.Header())
+	}
+}
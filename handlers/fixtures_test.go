@@ -0,0 +1,46 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"receipt-processor/models"
+)
+
+// targetReceipt is the "Target" example from the API description, worth 28 points.
+func targetReceipt() models.Receipt {
+	return models.Receipt{
+		Retailer:     "Target",
+		PurchaseDate: "2022-01-01",
+		PurchaseTime: "13:01",
+		Items: []models.Item{
+			{ShortDescription: "Mountain Dew 12PK", Price: "6.49"},
+			{ShortDescription: "Emils Cheese Pizza", Price: "12.25"},
+			{ShortDescription: "Knorr Creamy Chicken", Price: "1.26"},
+			{ShortDescription: "Doritos Nacho Cheese", Price: "3.35"},
+			{ShortDescription: "   Klarbrunn 12-PK 12 FL OZ  ", Price: "12.00"},
+		},
+		Total: "35.35",
+	}
+}
+
+// jsonRequest builds a request whose body is v encoded as JSON.
+func jsonRequest(t *testing.T, method, target string, v interface{}) *http.Request {
+	t.Helper()
+	body, err := json.Marshal(v)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	return httptest.NewRequest(method, target, bytes.NewReader(body))
+}
+
+// decodeBody decodes the recorded response body into v.
+func decodeBody(t *testing.T, rec *httptest.ResponseRecorder, v interface{}) {
+	t.Helper()
+	if err := json.NewDecoder(rec.Body).Decode(v); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+}
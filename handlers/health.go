@@ -0,0 +1,37 @@
+package handlers
+
+import (
+	"net/http"
+
+	"receipt-processor/models"
+	"receipt-processor/services"
+)
+
+// healthProbeID is the key written and removed by the readiness probe.
+const healthProbeID = "__health_probe__"
+
+// HealthHandler reports readiness by performing a set/get/delete round-trip
+// against the store. It responds 200 when the store is healthy and 503 when
+// any step of the round-trip fails.
+func HealthHandler(store services.ReceiptStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if err := probeStore(store); err != nil {
+			writeJSON(w, http.StatusServiceUnavailable, map[string]string{
+				"status": "unavailable",
+				"error":  err.Error(),
+			})
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+	}
+}
+
+func probeStore(store services.ReceiptStore) error {
+	if err := store.Set(healthProbeID, models.StoredReceipt{}); err != nil {
+		return err
+	}
+	if _, err := store.Get(healthProbeID); err != nil {
+		return err
+	}
+	return store.Delete(healthProbeID)
+}
@@ -0,0 +1,57 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"receipt-processor/models"
+	"receipt-processor/services"
+)
+
+// failingStore is a ReceiptStore whose every operation fails.
+type failingStore struct{}
+
+var errStoreDown = errors.New("store unavailable")
+
+func (failingStore) Get(string) (models.StoredReceipt, error) {
+	return models.StoredReceipt{}, errStoreDown
+}
+func (failingStore) Set(string, models.StoredReceipt) error { return errStoreDown }
+func (failingStore) Has(string) (bool, error)               { return false, errStoreDown }
+func (failingStore) Delete(string) error                    { return errStoreDown }
+func (failingStore) Range(func(string, models.StoredReceipt) bool) error {
+	return errStoreDown
+}
+
+func TestHealthHandler(t *testing.T) {
+	tests := []struct {
+		name   string
+		store  services.ReceiptStore
+		status int
+	}{
+		{"healthy store", services.NewMapStore(), http.StatusOK},
+		{"retention store", services.NewRetentionStore(services.NewMapStore(), time.Hour, services.SystemClock), http.StatusOK},
+		{"failing store", failingStore{}, http.StatusServiceUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			HealthHandler(tt.store)(rec, httptest.NewRequest(http.MethodGet, "/health", nil))
+			if rec.Code != tt.status {
+				t.Errorf("status = %d, want %d", rec.Code, tt.status)
+			}
+		})
+	}
+}
+
+func TestHealthHandlerRemovesProbe(t *testing.T) {
+	store := services.NewMapStore()
+	HealthHandler(store)(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/health", nil))
+	if ok, _ := store.Has(healthProbeID); ok {
+		t.Error("probe key left in store")
+	}
+}
@@ -0,0 +1,40 @@
+package handlers
+
+import (
+	"net/http"
+
+	"receipt-processor/services"
+)
+
+// processResponse is the JSON body returned for a processed receipt.
+type processResponse struct {
+	ID string `json:"id"`
+}
+
+// ProcessHandler serves POST /receipts/process: it processes the receipt in
+// the request body with p and returns its ID. With ?validate=true the
+// receipt is only validated as p would process it, answering 204 or 400,
+// and no ID is generated and nothing is stored.
+func ProcessHandler(p *services.Processor) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		receipt, err := decodeReceipt(r)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if r.URL.Query().Get("validate") == "true" {
+			if err := p.Validate(receipt); err != nil {
+				writeServiceError(w, err)
+				return
+			}
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		id, err := p.ProcessContext(r.Context(), receipt)
+		if err != nil {
+			writeServiceError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, processResponse{ID: id})
+	}
+}
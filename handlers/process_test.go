@@ -0,0 +1,75 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"receipt-processor/services"
+)
+
+func TestProcessHandler(t *testing.T) {
+	store := services.NewMapStore()
+	h := ProcessHandler(services.NewProcessor(store))
+
+	rec := httptest.NewRecorder()
+	h(rec, jsonRequest(t, http.MethodPost, "/receipts/process", targetReceipt()))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", rec.Code)
+	}
+	var resp processResponse
+	decodeBody(t, rec, &resp)
+	if ok, _ := store.Has(resp.ID); !ok {
+		t.Errorf("receipt not stored under returned ID %q", resp.ID)
+	}
+
+	rec = httptest.NewRecorder()
+	h(rec, jsonRequest(t, http.MethodPost, "/receipts/process", targetReceipt()))
+	if rec.Code != http.StatusConflict {
+		t.Errorf("duplicate: status = %d, want 409", rec.Code)
+	}
+}
+
+func TestProcessHandlerValidateOnly(t *testing.T) {
+	invalid := targetReceipt()
+	invalid.Total = "35"
+
+	tests := []struct {
+		name   string
+		body   interface{}
+		status int
+	}{
+		{"valid", targetReceipt(), http.StatusNoContent},
+		{"invalid", invalid, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		store := services.NewMapStore()
+		rec := httptest.NewRecorder()
+		ProcessHandler(services.NewProcessor(store))(rec,
+			jsonRequest(t, http.MethodPost, "/receipts/process?validate=true", tt.body))
+
+		if rec.Code != tt.status {
+			t.Errorf("%s: status = %d, want %d", tt.name, rec.Code, tt.status)
+		}
+		if n, _ := services.CountReceipts(store); n != 0 {
+			t.Errorf("%s: store holds %d receipts, want 0", tt.name, n)
+		}
+	}
+}
+
+func TestProcessHandlerValidateOnlyUsesProcessorClock(t *testing.T) {
+	// targetReceipt is dated 2022-01-01, so it is 10 days old by this clock
+	// and years old by the system clock.
+	p := services.NewProcessor(services.NewMapStore())
+	p.Clock = services.FixedClock(time.Date(2022, 1, 11, 12, 0, 0, 0, time.UTC))
+	p.Validation.MaxReceiptAge = 30 * 24 * time.Hour
+
+	for _, query := range []string{"?validate=true", ""} {
+		rec := httptest.NewRecorder()
+		ProcessHandler(p)(rec, jsonRequest(t, http.MethodPost, "/receipts/process"+query, targetReceipt()))
+		if rec.Code != http.StatusNoContent && rec.Code != http.StatusOK {
+			t.Errorf("%q: status = %d, want success", query, rec.Code)
+		}
+	}
+}
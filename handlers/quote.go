@@ -0,0 +1,31 @@
+package handlers
+
+import (
+	"net/http"
+
+	"receipt-processor/services"
+)
+
+// quoteResponse is the JSON body returned by QuoteHandler.
+type quoteResponse struct {
+	Points int `json:"points"`
+}
+
+// QuoteHandler serves POST /receipts/quote: the points the receipt in the
+// request body would earn if processed by p now. No ID is generated and
+// nothing is stored.
+func QuoteHandler(p *services.Processor) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		receipt, err := decodeReceipt(r)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		points, _, err := p.Quote(receipt)
+		if err != nil {
+			writeServiceError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, quoteResponse{Points: points})
+	}
+}
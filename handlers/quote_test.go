@@ -0,0 +1,57 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"receipt-processor/services"
+)
+
+func TestQuoteHandler(t *testing.T) {
+	rec := httptest.NewRecorder()
+	QuoteHandler(services.NewProcessor(services.NewMapStore()))(rec, jsonRequest(t, http.MethodPost, "/receipts/quote", targetReceipt()))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", rec.Code)
+	}
+	var resp quoteResponse
+	decodeBody(t, rec, &resp)
+	if resp.Points != 28 {
+		t.Errorf("points = %d, want 28", resp.Points)
+	}
+}
+
+func TestQuoteHandlerInvalidReceipt(t *testing.T) {
+	receipt := targetReceipt()
+	receipt.Items = nil
+	rec := httptest.NewRecorder()
+	QuoteHandler(services.NewProcessor(services.NewMapStore()))(rec, jsonRequest(t, http.MethodPost, "/receipts/quote", receipt))
+
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want 400", rec.Code)
+	}
+}
+
+func TestQuoteHandlerCommaSeparator(t *testing.T) {
+	p := services.NewProcessor(services.NewMapStore())
+	p.Validation.DecimalSeparator = ","
+	p.Rules.DecimalSeparator = ","
+	receipt := targetReceipt()
+	for i := range receipt.Items {
+		receipt.Items[i].Price = strings.Replace(receipt.Items[i].Price, ".", ",", 1)
+	}
+	receipt.Total = "35,35"
+
+	rec := httptest.NewRecorder()
+	QuoteHandler(p)(rec, jsonRequest(t, http.MethodPost, "/receipts/quote", receipt))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200: %s", rec.Code, rec.Body)
+	}
+	var resp quoteResponse
+	decodeBody(t, rec, &resp)
+	if resp.Points != 28 {
+		t.Errorf("points = %d, want 28", resp.Points)
+	}
+}
@@ -0,0 +1,25 @@
+package handlers
+
+import (
+	"net/http"
+
+	"receipt-processor/services"
+)
+
+// GetReceiptHandler serves GET /receipts/{id}: the receipt as it was stored.
+// IDs that p.ValidateID rejects get 400 and unknown IDs 404.
+func GetReceiptHandler(p *services.Processor) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id := r.PathValue("id")
+		if err := p.ValidateID(id); err != nil {
+			writeServiceError(w, err)
+			return
+		}
+		entry, err := p.Store.Get(id)
+		if err != nil {
+			writeServiceError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, entry.Receipt)
+	}
+}
@@ -0,0 +1,90 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"receipt-processor/models"
+	"receipt-processor/services"
+)
+
+// getWithID runs h for a GET request whose {id} path value is id.
+func getWithID(h http.HandlerFunc, path, id string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	req.SetPathValue("id", id)
+	rec := httptest.NewRecorder()
+	h(rec, req)
+	return rec
+}
+
+func TestGetReceiptHandler(t *testing.T) {
+	store := services.NewMapStore()
+	receipt := targetReceipt()
+	receipt.Tags = []string{"online"}
+	id, err := services.ProcessReceipt(receipt, store)
+	if err != nil {
+		t.Fatalf("ProcessReceipt: %v", err)
+	}
+
+	rec := getWithID(GetReceiptHandler(services.NewProcessor(store)), "/receipts/"+id, id)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", rec.Code)
+	}
+	var got models.Receipt
+	decodeBody(t, rec, &got)
+	want, _ := services.ValidateAndNormalize(receipt) // stored in canonical form
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("receipt = %+v, want %+v", got, want)
+	}
+}
+
+func TestGetReceiptHandlerErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		id     string
+		status int
+	}{
+		{"missing ID", "0000", http.StatusNotFound},
+		{"malformed ID", "not an id", http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := getWithID(GetReceiptHandler(services.NewProcessor(services.NewMapStore())), "/receipts/x", tt.id)
+			if rec.Code != tt.status {
+				t.Errorf("status = %d, want %d", rec.Code, tt.status)
+			}
+		})
+	}
+}
+
+func TestGetReceiptHandlerStrictIDs(t *testing.T) {
+	p := services.NewProcessor(services.NewMapStore())
+	p.IDFormat = services.IDFormatUUID
+	p.StrictIDs = true
+	id, err := p.Process(targetReceipt())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		id     string
+		status int
+	}{
+		{"stored UUID", id, http.StatusOK},
+		{"missing UUID", "00000000-0000-5000-8000-000000000000", http.StatusNotFound},
+		{"hex ID", services.ComputeReceiptID(targetReceipt()), http.StatusBadRequest},
+		{"arbitrary token", "missing", http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := getWithID(GetReceiptHandler(p), "/receipts/x", tt.id)
+			if rec.Code != tt.status {
+				t.Errorf("status = %d, want %d", rec.Code, tt.status)
+			}
+		})
+	}
+}
@@ -0,0 +1,34 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"receipt-processor/models"
+	"receipt-processor/services"
+)
+
+// decodeReceipt decodes a receipt from the request body.
+func decodeReceipt(r *http.Request) (models.Receipt, error) {
+	var receipt models.Receipt
+	if err := json.NewDecoder(r.Body).Decode(&receipt); err != nil {
+		return models.Receipt{}, errors.New("The receipt is invalid.")
+	}
+	return receipt, nil
+}
+
+// writeServiceError maps an error from the services package to a response.
+func writeServiceError(w http.ResponseWriter, err error) {
+	var verr *services.ValidationError
+	switch {
+	case errors.As(err, &verr):
+		writeError(w, http.StatusBadRequest, verr.Message)
+	case errors.Is(err, services.ErrNotFound):
+		writeError(w, http.StatusNotFound, "No receipt found for that ID.")
+	case errors.Is(err, services.ErrDuplicateReceipt):
+		writeError(w, http.StatusConflict, "This receipt has already been processed.")
+	default:
+		writeError(w, http.StatusInternalServerError, err.Error())
+	}
+}
@@ -0,0 +1,24 @@
+package handlers
+
+import (
+	"net/http"
+
+	"receipt-processor/services"
+)
+
+// TraceHeader carries the trace ID of a request and its response.
+const TraceHeader = "X-Trace-ID"
+
+// TraceMiddleware puts the request's trace ID, taken from TraceHeader or
+// generated when absent, into the request context and echoes it back in
+// the response header.
+func TraceMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceID := r.Header.Get(TraceHeader)
+		if traceID == "" {
+			traceID = services.NewTraceID()
+		}
+		w.Header().Set(TraceHeader, traceID)
+		next.ServeHTTP(w, r.WithContext(services.WithTraceID(r.Context(), traceID)))
+	})
+}
@@ -0,0 +1,30 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"receipt-processor/services"
+)
+
+func TestTraceMiddleware(t *testing.T) {
+	var seen string
+	h := TraceMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen = services.TraceIDFromContext(r.Context())
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(TraceHeader, "abc123")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if seen != "abc123" || rec.Header().Get(TraceHeader) != "abc123" {
+		t.Errorf("context trace = %q, header = %q; want abc123", seen, rec.Header().Get(TraceHeader))
+	}
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	if seen == "" || rec.Header().Get(TraceHeader) != seen {
+		t.Errorf("generated trace = %q, header = %q", seen, rec.Header().Get(TraceHeader))
+	}
+}
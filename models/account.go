@@ -0,0 +1,10 @@
+package models
+
+// Account is a loyalty member's running point balance.
+type Account struct {
+	ID          string `json:"id"`
+	TotalPoints int    `json:"totalPoints"`
+	// AppliedReceipts holds the IDs of receipts already credited, so a
+	// receipt can't be counted twice.
+	AppliedReceipts map[string]bool `json:"appliedReceipts,omitempty"`
+}
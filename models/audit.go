@@ -0,0 +1,12 @@
+package models
+
+import "time"
+
+// AuditEntry records the outcome of one receipt submission.
+type AuditEntry struct {
+	TraceID   string    `json:"traceId"`
+	ReceiptID string    `json:"receiptId,omitempty"`
+	Points    int       `json:"points"`
+	Error     string    `json:"error,omitempty"`
+	At        time.Time `json:"at"`
+}
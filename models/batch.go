@@ -0,0 +1,33 @@
+package models
+
+// InvalidReceipt pairs a rejected receipt's position in a batch with the
+// reason it failed validation.
+type InvalidReceipt struct {
+	Index   int     `json:"index"`
+	Receipt Receipt `json:"receipt"`
+	Err     error   `json:"-"`
+}
+
+// BatchValidationReport summarizes how a batch of receipts fared in
+// validation. ByCode counts the invalid receipts by validation error code.
+type BatchValidationReport struct {
+	Total   int            `json:"total"`
+	Valid   int            `json:"valid"`
+	Invalid int            `json:"invalid"`
+	ByCode  map[string]int `json:"byCode"`
+}
+
+// ImportReport summarizes an import of receipt files. Skipped counts entries
+// that were not receipt files; Duplicates counts receipts already stored.
+type ImportReport struct {
+	Imported   int             `json:"imported"`
+	Duplicates int             `json:"duplicates"`
+	Skipped    int             `json:"skipped"`
+	Failed     []ImportFailure `json:"failed,omitempty"`
+}
+
+// ImportFailure is a file that could not be imported and why.
+type ImportFailure struct {
+	File  string `json:"file"`
+	Error string `json:"error"`
+}
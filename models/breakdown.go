@@ -0,0 +1,30 @@
+package models
+
+import "time"
+
+// Contribution is the points a single scoring rule awarded a receipt.
+type Contribution struct {
+	Rule   string `json:"rule"`
+	Points int    `json:"points"`
+}
+
+// ScoreResult is a self-describing score: the points, how each rule
+// contributed, and the version of the rule config that produced them.
+type ScoreResult struct {
+	Points            int            `json:"points"`
+	Breakdown         []Contribution `json:"breakdown"`
+	RuleConfigVersion string         `json:"ruleConfigVersion"`
+	// ExpiresAt is when the points lapse, set when the rule config has a
+	// PointsLifetime.
+	ExpiresAt *time.Time `json:"expiresAt,omitempty"`
+}
+
+// ProcessResult describes an accepted submission in full: the ID it was
+// stored under, the receipt in normalized form, and its points together
+// with how each rule contributed.
+type ProcessResult struct {
+	ID        string         `json:"id"`
+	Receipt   Receipt        `json:"receipt"`
+	Points    int            `json:"points"`
+	Breakdown []Contribution `json:"breakdown"`
+}
@@ -0,0 +1,195 @@
+package models
+
+import "time"
+
+// Default scoring values, as set out in the receipt processor rules.
+const (
+	DefaultPointsPerRetailerChar      = 1
+	DefaultRoundDollarPoints          = 50
+	DefaultQuarterMultiplePoints      = 25
+	DefaultItemsPerGroup              = 2
+	DefaultPointsPerGroup             = 5
+	DefaultDescriptionLengthMultiple  = 3
+	DefaultDescriptionPriceMultiplier = 0.2
+	DefaultOddDayPoints               = 6
+	DefaultEvenDayPoints              = 0
+	DefaultTimeWindowStart            = "14:00"
+	DefaultTimeWindowEnd              = "16:00"
+	DefaultTimeWindowPoints           = 10
+
+	// DefaultRuleConfigVersion identifies the rule set returned by DefaultRuleConfig.
+	DefaultRuleConfigVersion = "default-v1"
+
+	// RuleConfigSchemaVersion is the layout of the serialized RuleConfig.
+	// Version 1 is the original JSON form; documents written before the
+	// field existed have no schemaVersion and are version 1.
+	RuleConfigSchemaVersion = 1
+)
+
+// RuleConfig holds the point values and thresholds used by the scoring rules.
+type RuleConfig struct {
+	// SchemaVersion is the RuleConfigSchemaVersion the config was serialized
+	// with, so older documents can be migrated when loaded.
+	SchemaVersion int `json:"schemaVersion"`
+	// Version labels this rule set so scores can be traced to the rules that
+	// produced them. Change it whenever point values change.
+	Version string `json:"version"`
+	// PointsPerRetailerChar is awarded for each alphanumeric character of the
+	// retailer name.
+	PointsPerRetailerChar int `json:"pointsPerRetailerChar"`
+	// LegacyRetailerCount counts every non-space byte of the retailer name,
+	// punctuation included, instead of only letters and digits.
+	LegacyRetailerCount bool `json:"legacyRetailerCount"`
+	// RetailerAliases maps retailer names to the canonical name the
+	// retailer-name rule counts instead. A key matches a name equal to it
+	// after trimming, or failing that, a name it matches in full as a
+	// regular expression; regular expressions are tried in key order.
+	RetailerAliases map[string]string `json:"retailerAliases"`
+	// RoundDollarPoints is awarded when the total is a round dollar amount.
+	RoundDollarPoints int `json:"roundDollarPoints"`
+	// NearRoundThreshold is the distance in cents from a whole dollar within
+	// which a non-round total earns NearRoundBonus instead. Zero disables it.
+	NearRoundThreshold int `json:"nearRoundThreshold"`
+	// NearRoundBonus is awarded for totals within NearRoundThreshold of a whole dollar.
+	NearRoundBonus int `json:"nearRoundBonus"`
+	// QuarterMultiplePoints is awarded when the total is a multiple of 0.25.
+	QuarterMultiplePoints int `json:"quarterMultiplePoints"`
+	// AllowNonPositiveTotalBonuses lets zero and negative totals, such as
+	// refund-only receipts, earn the round-dollar and quarter-multiple
+	// bonuses. By default they earn neither.
+	AllowNonPositiveTotalBonuses bool `json:"allowNonPositiveTotalBonuses"`
+	// MutuallyExclusiveTotalRules awards only the higher of the round-dollar
+	// and quarter-multiple bonuses when a total qualifies for both, so "10.00"
+	// earns 50 rather than 75.
+	MutuallyExclusiveTotalRules bool `json:"mutuallyExclusiveTotalRules"`
+	// ItemsPerGroup is the size of the item groups that earn PointsPerGroup.
+	ItemsPerGroup int `json:"itemsPerGroup"`
+	// PointsPerGroup is awarded for every ItemsPerGroup items on the receipt.
+	PointsPerGroup int `json:"pointsPerGroup"`
+	// RoundUpPartialGroup awards a leftover partial group as a full one
+	// instead of discarding it.
+	RoundUpPartialGroup bool `json:"roundUpPartialGroup"`
+	// ProratePartialGroup awards a leftover partial group its share of
+	// PointsPerGroup, rounded down, so with the defaults a third item adds 2
+	// of the 2.5 points. RoundUpPartialGroup takes precedence.
+	ProratePartialGroup bool `json:"proratePartialGroup"`
+	// ExcludeTaxItems leaves tax lines out of the item count.
+	ExcludeTaxItems bool `json:"excludeTaxItems"`
+	// DescriptionLengthMultiple is the trimmed description length divisor that
+	// makes an item eligible for price-based points.
+	DescriptionLengthMultiple int `json:"descriptionLengthMultiple"`
+	// DescriptionLengthMode is how description length is measured for
+	// DescriptionLengthMultiple.
+	DescriptionLengthMode LengthMode `json:"descriptionLengthMode"`
+	// DescriptionPriceMultiplier is multiplied by an eligible item's price and
+	// rounded up to give that item's points.
+	DescriptionPriceMultiplier float64 `json:"descriptionPriceMultiplier"`
+	// MaxPerItemPoints caps what any single item can contribute through the
+	// per-item rules. Zero leaves items uncapped.
+	MaxPerItemPoints int `json:"maxPerItemPoints"`
+	// BestItemOnly makes the per-item rules award only the single item
+	// contributing the most, after MaxPerItemPoints, instead of the sum.
+	BestItemOnly bool `json:"bestItemOnly"`
+	// OddDayPoints is awarded by the odd_day rule when the purchase date's
+	// day of the month is odd, EvenDayPoints by the even_day rule when it is
+	// even.
+	OddDayPoints  int `json:"oddDayPoints"`
+	EvenDayPoints int `json:"evenDayPoints"`
+	// TimeWindows award points when the purchase time falls strictly inside one.
+	TimeWindows []TimeWindow `json:"timeWindows"`
+	// TimeWindowGrace is how close to a time window, before it opens or
+	// after it closes, a purchase must be to earn TimeWindowGracePercent of
+	// the window's points, rounded down. Zero disables the ramp. In JSON it
+	// is a count of nanoseconds.
+	TimeWindowGrace        time.Duration `json:"timeWindowGrace"`
+	TimeWindowGracePercent int           `json:"timeWindowGracePercent"`
+	// MaxSubmissionDelay is how long after the purchase a receipt may be
+	// submitted before LateSubmissionPenalty applies. Zero disables the penalty.
+	// In JSON it is a count of nanoseconds.
+	MaxSubmissionDelay time.Duration `json:"maxSubmissionDelay"`
+	// LateSubmissionPenalty is subtracted from late receipts, never taking
+	// the score below zero.
+	LateSubmissionPenalty int `json:"lateSubmissionPenalty"`
+	// PointsLifetime is how long after the start of the purchase date, in
+	// UTC, a receipt's points remain valid. Zero means they never expire. In
+	// JSON it is a count of nanoseconds.
+	PointsLifetime time.Duration `json:"pointsLifetime"`
+	// BasePoints is added to every receipt's score before rounding.
+	BasePoints int `json:"basePoints"`
+	// MaxPoints caps the final score, BasePoints and rounding included.
+	// Zero means no cap.
+	MaxPoints int `json:"maxPoints"`
+	// RoundFinalTo rounds the summed score to a multiple of this value using
+	// RoundingMode. Zero and one leave the score as is.
+	RoundFinalTo int `json:"roundFinalTo"`
+	// RoundingMode is the direction RoundFinalTo rounds in.
+	RoundingMode RoundingMode `json:"roundingMode"`
+	// DecimalSeparator is the separator used in amounts, "." or ",". Empty
+	// means ".".
+	DecimalSeparator string `json:"decimalSeparator"`
+	// EnabledRules switches built-in rules, by name such as "time_window",
+	// on or off. A rule mapped to false is skipped entirely: it is not
+	// evaluated and is left out of breakdowns. Rules not listed are enabled.
+	EnabledRules map[string]bool `json:"enabledRules"`
+	// RuleGates maps rule names, such as "time_window", to a predicate the
+	// receipt must satisfy for that rule to award points. Rules without a
+	// gate always apply. Gates are code, so they are not part of the JSON
+	// form, and ScoreAccumulator does not apply them.
+	RuleGates map[string]ReceiptPredicate `json:"-"`
+}
+
+// ReceiptPredicate is a yes/no test on a receipt.
+type ReceiptPredicate func(receipt Receipt) bool
+
+// RoundingMode is a rounding direction for RoundFinalTo.
+type RoundingMode string
+
+const (
+	// RoundNearest rounds to the nearest multiple, halves rounding up. It is
+	// the default, used when the mode is empty.
+	RoundNearest RoundingMode = "nearest"
+	RoundUp      RoundingMode = "up"
+	RoundDown    RoundingMode = "down"
+)
+
+// LengthMode is a way of measuring the length of a description.
+type LengthMode string
+
+const (
+	// LengthBytes counts the bytes of the trimmed description. It is the
+	// default, used when the mode is empty.
+	LengthBytes LengthMode = "bytes"
+	// LengthVisibleRunes counts the characters that are neither whitespace
+	// nor control characters.
+	LengthVisibleRunes LengthMode = "visible_runes"
+)
+
+// TimeWindow is a bonus window on the purchase time. Start and End use the
+// 24-hour "15:04" layout and are exclusive bounds. A Start later than End
+// makes the window wrap around midnight, so 22:00-02:00 covers late night.
+type TimeWindow struct {
+	Start  string `json:"start"`
+	End    string `json:"end"`
+	Points int    `json:"points"`
+}
+
+// DefaultRuleConfig returns the standard receipt scoring rules.
+func DefaultRuleConfig() RuleConfig {
+	return RuleConfig{
+		SchemaVersion:              RuleConfigSchemaVersion,
+		Version:                    DefaultRuleConfigVersion,
+		PointsPerRetailerChar:      DefaultPointsPerRetailerChar,
+		RoundDollarPoints:          DefaultRoundDollarPoints,
+		QuarterMultiplePoints:      DefaultQuarterMultiplePoints,
+		ItemsPerGroup:              DefaultItemsPerGroup,
+		PointsPerGroup:             DefaultPointsPerGroup,
+		DescriptionLengthMultiple:  DefaultDescriptionLengthMultiple,
+		DescriptionPriceMultiplier: DefaultDescriptionPriceMultiplier,
+		OddDayPoints:               DefaultOddDayPoints,
+		EvenDayPoints:              DefaultEvenDayPoints,
+		TimeWindows: []TimeWindow{
+			{Start: DefaultTimeWindowStart, End: DefaultTimeWindowEnd, Points: DefaultTimeWindowPoints},
+		},
+		RoundFinalTo: 1,
+	}
+}
@@ -0,0 +1,45 @@
+package models
+
+import "testing"
+
+func TestDefaultRuleConfigMatchesConstants(t *testing.T) {
+	cfg := DefaultRuleConfig()
+
+	ints := []struct {
+		name      string
+		got, want int
+	}{
+		{"PointsPerRetailerChar", cfg.PointsPerRetailerChar, DefaultPointsPerRetailerChar},
+		{"RoundDollarPoints", cfg.RoundDollarPoints, DefaultRoundDollarPoints},
+		{"QuarterMultiplePoints", cfg.QuarterMultiplePoints, DefaultQuarterMultiplePoints},
+		{"ItemsPerGroup", cfg.ItemsPerGroup, DefaultItemsPerGroup},
+		{"PointsPerGroup", cfg.PointsPerGroup, DefaultPointsPerGroup},
+		{"DescriptionLengthMultiple", cfg.DescriptionLengthMultiple, DefaultDescriptionLengthMultiple},
+		{"OddDayPoints", cfg.OddDayPoints, DefaultOddDayPoints},
+		{"EvenDayPoints", cfg.EvenDayPoints, DefaultEvenDayPoints},
+	}
+	for _, c := range ints {
+		if c.got != c.want {
+			t.Errorf("%s = %d, want %d", c.name, c.got, c.want)
+		}
+	}
+	if cfg.DescriptionPriceMultiplier != DefaultDescriptionPriceMultiplier {
+		t.Errorf("DescriptionPriceMultiplier = %v, want %v",
+			cfg.DescriptionPriceMultiplier, DefaultDescriptionPriceMultiplier)
+	}
+
+	want := TimeWindow{Start: DefaultTimeWindowStart, End: DefaultTimeWindowEnd, Points: DefaultTimeWindowPoints}
+	if len(cfg.TimeWindows) != 1 || cfg.TimeWindows[0] != want {
+		t.Errorf("TimeWindows = %+v, want [%+v]", cfg.TimeWindows, want)
+	}
+}
+
+func TestDefaultRuleConfigSpecValues(t *testing.T) {
+	// Pin the constants to the published rules so they can't drift together.
+	if DefaultRoundDollarPoints != 50 || DefaultQuarterMultiplePoints != 25 ||
+		DefaultItemsPerGroup != 2 || DefaultPointsPerGroup != 5 || DefaultDescriptionLengthMultiple != 3 ||
+		DefaultDescriptionPriceMultiplier != 0.2 || DefaultOddDayPoints != 6 ||
+		DefaultTimeWindowPoints != 10 || DefaultPointsPerRetailerChar != 1 {
+		t.Error("default scoring constants differ from the published rules")
+	}
+}
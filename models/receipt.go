@@ -0,0 +1,63 @@
+package models
+
+import "time"
+
+// Receipt is a purchase receipt as submitted by a client.
+type Receipt struct {
+	Retailer     string `json:"retailer"`
+	PurchaseDate string `json:"purchaseDate"`
+	PurchaseTime string `json:"purchaseTime"`
+	Items        []Item `json:"items"`
+	Total        string `json:"total"`
+	// Subtotal and Tax optionally break down the total. When both are
+	// present they must add up to Total; scoring always uses Total.
+	Subtotal string `json:"subtotal,omitempty"`
+	Tax      string `json:"tax,omitempty"`
+	// ImageURL optionally references an image of the paper receipt. It is
+	// kept for provenance only and takes no part in scoring or the receipt ID.
+	ImageURL string `json:"imageUrl,omitempty"`
+	// Tags are caller-defined labels used for filtering. Like ImageURL they
+	// are not part of scoring or the receipt ID.
+	Tags []string `json:"tags,omitempty"`
+	// Notes is free-form context from the submitter, stored as given and
+	// not part of scoring or the receipt ID.
+	Notes string `json:"notes,omitempty"`
+}
+
+// Item is a single line item on a receipt.
+type Item struct {
+	ShortDescription string `json:"shortDescription"`
+	Price            string `json:"price"`
+	// IsTax marks a tax line rather than a purchased product.
+	IsTax bool `json:"isTax,omitempty"`
+	// SKU optionally identifies the product. It is part of the receipt ID,
+	// so items differing only by SKU stay distinct, but not of scoring.
+	SKU string `json:"sku,omitempty"`
+	// Discount optionally reduces the price the item is scored at. It may
+	// not exceed Price.
+	Discount string `json:"discount,omitempty"`
+}
+
+// StoredReceipt is the value kept in a receipt store: the receipt as
+// accepted together with the points it was awarded.
+type StoredReceipt struct {
+	Receipt Receipt `json:"receipt"`
+	Points  int     `json:"points"`
+	// Breakdown is how Points was made up when the receipt was scored,
+	// summing to Points. Entries stored without one leave it nil.
+	Breakdown []Contribution `json:"breakdown,omitempty"`
+	// StoredAt is when the receipt was first accepted.
+	StoredAt time.Time `json:"storedAt"`
+	// Status is where the receipt is in manual review. Empty means
+	// StatusPending.
+	Status ReceiptStatus `json:"status,omitempty"`
+}
+
+// ReceiptStatus is a stored receipt's review status.
+type ReceiptStatus string
+
+const (
+	StatusPending  ReceiptStatus = "pending"
+	StatusVerified ReceiptStatus = "verified"
+	StatusRejected ReceiptStatus = "rejected"
+)
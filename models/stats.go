@@ -0,0 +1,14 @@
+package models
+
+// PointsStats summarizes the points awarded across a set of receipts. Count
+// is zero, and every statistic with it, when there were no receipts.
+type PointsStats struct {
+	Count  int     `json:"count"`
+	Min    int     `json:"min"`
+	Max    int     `json:"max"`
+	Mean   float64 `json:"mean"`
+	Median float64 `json:"median"`
+	// P90 is the 90th percentile by the nearest-rank method: the smallest
+	// score at least 90% of receipts do not exceed.
+	P90 int `json:"p90"`
+}
@@ -0,0 +1,74 @@
+package models
+
+import "time"
+
+// ValidationConfig holds optional validation checks applied on top of the
+// receipt schema. The zero value enables none of them.
+type ValidationConfig struct {
+	// BusinessHours maps weekdays to opening hours. When non-empty, purchases
+	// outside the hours of their weekday, or on a weekday with no entry, are
+	// rejected. An empty map disables the check.
+	BusinessHours map[time.Weekday]OpeningHours
+	// CheckItemSum requires item prices net of discounts, tax lines
+	// included, to add up to the total.
+	CheckItemSum bool
+	// ToleranceCents is how far the item sum may differ from the total and
+	// still pass CheckItemSum. Zero requires an exact match.
+	ToleranceCents int
+	// RequireSortedItems rejects receipts whose items are not in ascending
+	// price order. Equal prices may appear in any order.
+	RequireSortedItems bool
+	// MaxDescriptionLength is the longest item description accepted, in
+	// characters. Zero means no limit.
+	MaxDescriptionLength int
+	// AllowEmptyDescriptions accepts items whose ShortDescription is empty or
+	// only whitespace, such as unlabeled line items.
+	AllowEmptyDescriptions bool
+	// MaxDistinctItems is the most distinct items a receipt may list, where
+	// items with the same trimmed description and price count once. Zero
+	// means no limit.
+	MaxDistinctItems int
+	// RejectDuplicateItems rejects receipts listing two exactly identical
+	// items, which usually means a line was entered twice.
+	RejectDuplicateItems bool
+	// MaxReceiptAge rejects receipts purchased longer than this before Now.
+	// Purchase date and time are interpreted as UTC. Zero disables the check.
+	MaxReceiptAge time.Duration
+	// Now supplies the current time for MaxReceiptAge. Nil means time.Now;
+	// Processor fills it from its Clock.
+	Now func() time.Time
+	// RetailerBlocklist rejects receipts from the listed retailers. Names are
+	// matched case-insensitively with surrounding whitespace ignored.
+	RetailerBlocklist []string
+	// DecimalSeparator is the separator used in amounts, "." or ",". Amounts
+	// are normalized to "." before they are checked. Empty means ".".
+	DecimalSeparator string
+	// TrimTotals trims surrounding whitespace from the total and the other
+	// amounts, item prices included, before they are checked, rather than
+	// rejecting it. Processor stores and scores the trimmed amounts.
+	TrimTotals bool
+	// RelaxedAmounts accepts amounts with zero to two decimal places, such
+	// as "12", "12.5" and "12.50", instead of exactly two.
+	RelaxedAmounts bool
+	// ConsistentDecimals, with RelaxedAmounts, requires the total and every
+	// item price to have the same number of decimal places.
+	ConsistentDecimals bool
+}
+
+// DefaultMaxDescriptionLength is the item description limit applied by
+// DefaultValidationConfig.
+const DefaultMaxDescriptionLength = 256
+
+// OpeningHours is a store's opening window for one day, in the 24-hour
+// "15:04" layout. Open is inclusive and Close is exclusive.
+type OpeningHours struct {
+	Open  string
+	Close string
+}
+
+// DefaultValidationConfig returns the validation config used by ValidateReceipt.
+func DefaultValidationConfig() ValidationConfig {
+	return ValidationConfig{
+		MaxDescriptionLength: DefaultMaxDescriptionLength,
+	}
+}
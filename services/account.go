@@ -0,0 +1,37 @@
+package services
+
+import (
+	"errors"
+
+	"receipt-processor/models"
+)
+
+// ErrAlreadyApplied is returned when a receipt has already been credited to
+// an account.
+var ErrAlreadyApplied = errors.New("receipt already applied to account")
+
+// ApplyToAccount validates and scores a receipt under cfg, credits the
+// points to account and records the receipt's ID. As with Process, the
+// receipt is scored and identified in its normalized form, so a receipt
+// already applied to the account in any spelling is rejected with
+// ErrAlreadyApplied, leaving it unchanged.
+func ApplyToAccount(account *models.Account, receipt models.Receipt, cfg models.RuleConfig) (added int, err error) {
+	if err := ValidateReceipt(receipt); err != nil {
+		return 0, err
+	}
+	receipt = normalizeReceipt(receipt)
+	id := ComputeReceiptID(receipt)
+	if account.AppliedReceipts[id] {
+		return 0, ErrAlreadyApplied
+	}
+	points, err := CalculatePointsWithConfig(receipt, cfg)
+	if err != nil {
+		return 0, err
+	}
+	if account.AppliedReceipts == nil {
+		account.AppliedReceipts = make(map[string]bool)
+	}
+	account.AppliedReceipts[id] = true
+	account.TotalPoints += points
+	return points, nil
+}
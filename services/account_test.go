@@ -0,0 +1,65 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"receipt-processor/models"
+)
+
+func TestApplyToAccount(t *testing.T) {
+	account := &models.Account{ID: "member-1", TotalPoints: 100}
+	cfg := models.DefaultRuleConfig()
+
+	added, err := ApplyToAccount(account, targetReceipt(), cfg)
+	if err != nil {
+		t.Fatalf("first apply: %v", err)
+	}
+	if added != 28 || account.TotalPoints != 128 {
+		t.Errorf("added = %d, total = %d; want 28, 128", added, account.TotalPoints)
+	}
+
+	added, err = ApplyToAccount(account, targetReceipt(), cfg)
+	if !errors.Is(err, ErrAlreadyApplied) {
+		t.Fatalf("duplicate apply: error = %v, want ErrAlreadyApplied", err)
+	}
+	if added != 0 || account.TotalPoints != 128 {
+		t.Errorf("after duplicate: added = %d, total = %d; want 0, 128", added, account.TotalPoints)
+	}
+
+	if _, err := ApplyToAccount(account, roundReceipt(), cfg); err != nil {
+		t.Fatalf("second receipt: %v", err)
+	}
+	if account.TotalPoints != 233 || len(account.AppliedReceipts) != 2 {
+		t.Errorf("total = %d, applied = %d; want 233, 2", account.TotalPoints, len(account.AppliedReceipts))
+	}
+}
+
+func TestApplyToAccountNonCanonicalResubmission(t *testing.T) {
+	account := &models.Account{}
+	cfg := models.DefaultRuleConfig()
+	if _, err := ApplyToAccount(account, targetReceipt(), cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	padded := targetReceipt()
+	padded.Total = "035.35"
+	if _, err := ApplyToAccount(account, padded, cfg); !errors.Is(err, ErrAlreadyApplied) {
+		t.Errorf("error = %v, want ErrAlreadyApplied", err)
+	}
+	if account.TotalPoints != 28 {
+		t.Errorf("total = %d, want 28", account.TotalPoints)
+	}
+}
+
+func TestApplyToAccountInvalidReceipt(t *testing.T) {
+	account := &models.Account{}
+	receipt := targetReceipt()
+	receipt.Total = "x"
+	if _, err := ApplyToAccount(account, receipt, models.DefaultRuleConfig()); err == nil {
+		t.Fatal("expected validation error")
+	}
+	if account.TotalPoints != 0 || len(account.AppliedReceipts) != 0 {
+		t.Errorf("account changed after a rejected receipt: %+v", account)
+	}
+}
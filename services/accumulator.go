@@ -0,0 +1,106 @@
+package services
+
+import "receipt-processor/models"
+
+// ScoreAccumulator scores a receipt incrementally as it is entered. Each
+// setter re-evaluates only the rules that depend on the changed field, and
+// AddItem adds only the new item's contribution. Points always equals
+// CalculatePointsWithConfig on the equivalent full receipt, except that
+// RuleGates, which need the whole receipt, are not applied.
+type ScoreAccumulator struct {
+	cfg models.RuleConfig
+
+	retailerPoints int
+	totalPoints    int
+	datePoints     int
+	timePoints     int
+	itemCount      int
+	itemPoints     int
+}
+
+// NewScoreAccumulator returns an empty accumulator scoring under cfg.
+func NewScoreAccumulator(cfg models.RuleConfig) *ScoreAccumulator {
+	return &ScoreAccumulator{cfg: cfg}
+}
+
+// SetRetailer sets or replaces the retailer name.
+func (a *ScoreAccumulator) SetRetailer(retailer string) error {
+	points, err := retailerNamePoints(models.Receipt{Retailer: retailer}, a.cfg)
+	if err != nil {
+		return err
+	}
+	a.retailerPoints = a.enabled("retailer_name", points)
+	return nil
+}
+
+// SetTotal sets or replaces the receipt total.
+func (a *ScoreAccumulator) SetTotal(total string) error {
+	receipt := models.Receipt{Total: total}
+	round, err := roundDollarPoints(receipt, a.cfg)
+	if err != nil {
+		return err
+	}
+	quarter, err := quarterMultiplePoints(receipt, a.cfg)
+	if err != nil {
+		return err
+	}
+	a.totalPoints = a.enabled("round_dollar", round) + a.enabled("quarter_multiple", quarter)
+	return nil
+}
+
+// SetDate sets or replaces the purchase date.
+func (a *ScoreAccumulator) SetDate(date string) error {
+	receipt := models.Receipt{PurchaseDate: date}
+	odd, err := oddDayPoints(receipt, a.cfg)
+	if err != nil {
+		return err
+	}
+	even, err := evenDayPoints(receipt, a.cfg)
+	if err != nil {
+		return err
+	}
+	a.datePoints = a.enabled("odd_day", odd) + a.enabled("even_day", even)
+	return nil
+}
+
+// SetTime sets or replaces the purchase time.
+func (a *ScoreAccumulator) SetTime(purchaseTime string) error {
+	points, err := timeWindowPoints(models.Receipt{PurchaseTime: purchaseTime}, a.cfg)
+	if err != nil {
+		return err
+	}
+	a.timePoints = a.enabled("time_window", points)
+	return nil
+}
+
+// AddItem appends an item. On error the accumulator is left unchanged.
+func (a *ScoreAccumulator) AddItem(item models.Item) error {
+	points, err := descriptionLengthPoints(models.Receipt{Items: []models.Item{item}}, a.cfg)
+	if err != nil {
+		return err
+	}
+	points = a.enabled("description_length", points)
+	if a.cfg.BestItemOnly {
+		a.itemPoints = max(a.itemPoints, points)
+	} else {
+		a.itemPoints += points
+	}
+	if countsAsItem(item, a.cfg) {
+		a.itemCount++
+	}
+	return nil
+}
+
+// Points returns the running point total.
+func (a *ScoreAccumulator) Points() int {
+	return finalPoints(a.retailerPoints+a.totalPoints+a.datePoints+a.timePoints+
+		a.itemPoints+a.enabled("item_pairs", itemCountPoints(a.itemCount, a.cfg)), a.cfg)
+}
+
+// enabled returns points, or zero if the named rule is disabled.
+func (a *ScoreAccumulator) enabled(name string, points int) int {
+	if !ruleEnabled(name, a.cfg) {
+		return 0
+	}
+	return points
+}
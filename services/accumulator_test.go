@@ -0,0 +1,106 @@
+package services
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+	"testing"
+
+	"receipt-processor/models"
+)
+
+func randomItem(rng *rand.Rand) models.Item {
+	return models.Item{
+		ShortDescription: strings.Repeat("a", 1+rng.Intn(12)),
+		Price:            fmt.Sprintf("%d.%02d", rng.Intn(50), rng.Intn(100)),
+	}
+}
+
+func TestScoreAccumulatorMatchesFullScoring(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	cfg := models.DefaultRuleConfig()
+
+	for run := 0; run < 200; run++ {
+		receipt := models.Receipt{
+			Retailer:     strings.Repeat("x", 1+rng.Intn(10)),
+			PurchaseDate: fmt.Sprintf("2022-03-%02d", 1+rng.Intn(28)),
+			PurchaseTime: fmt.Sprintf("%02d:%02d", rng.Intn(24), rng.Intn(60)),
+			Total:        fmt.Sprintf("%d.%02d", rng.Intn(100), 25*rng.Intn(4)+rng.Intn(2)),
+		}
+		acc := NewScoreAccumulator(cfg)
+		for _, err := range []error{
+			acc.SetRetailer(receipt.Retailer),
+			acc.SetDate(receipt.PurchaseDate),
+			acc.SetTime(receipt.PurchaseTime),
+			acc.SetTotal(receipt.Total),
+		} {
+			if err != nil {
+				t.Fatalf("setter: %v", err)
+			}
+		}
+
+		for n := rng.Intn(10); n > 0; n-- {
+			item := randomItem(rng)
+			receipt.Items = append(receipt.Items, item)
+			if err := acc.AddItem(item); err != nil {
+				t.Fatalf("AddItem: %v", err)
+			}
+
+			want, err := CalculatePointsWithConfig(receipt, cfg)
+			if err != nil {
+				t.Fatalf("CalculatePointsWithConfig: %v", err)
+			}
+			if got := acc.Points(); got != want {
+				t.Fatalf("run %d after %d items: Points() = %d, want %d (%+v)",
+					run, len(receipt.Items), got, want, receipt)
+			}
+		}
+	}
+}
+
+func TestScoreAccumulatorReplacesTotal(t *testing.T) {
+	acc := NewScoreAccumulator(models.DefaultRuleConfig())
+	if err := acc.SetTotal("10.00"); err != nil {
+		t.Fatal(err)
+	}
+	if err := acc.SetTotal("10.01"); err != nil {
+		t.Fatal(err)
+	}
+	if got := acc.Points(); got != 0 {
+		t.Errorf("Points() = %d, want 0 after replacing a round total", got)
+	}
+}
+
+func TestScoreAccumulatorRejectsBadItem(t *testing.T) {
+	acc := NewScoreAccumulator(models.DefaultRuleConfig())
+	if err := acc.AddItem(models.Item{ShortDescription: "abc", Price: "x"}); err == nil {
+		t.Fatal("expected error for unparseable price")
+	}
+	if got := acc.Points(); got != 0 {
+		t.Errorf("Points() = %d, want 0", got)
+	}
+}
+
+func TestScoreAccumulatorBestItemOnly(t *testing.T) {
+	cfg := models.DefaultRuleConfig()
+	cfg.BestItemOnly = true
+	receipt := targetReceipt()
+
+	acc := NewScoreAccumulator(cfg)
+	acc.SetRetailer(receipt.Retailer)
+	acc.SetDate(receipt.PurchaseDate)
+	acc.SetTime(receipt.PurchaseTime)
+	acc.SetTotal(receipt.Total)
+	for _, item := range receipt.Items {
+		if err := acc.AddItem(item); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want, err := CalculatePointsWithConfig(receipt, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := acc.Points(); got != want {
+		t.Errorf("Points() = %d, want %d", got, want)
+	}
+}
@@ -0,0 +1,33 @@
+package services
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"unicode/utf8"
+
+	"receipt-processor/models"
+)
+
+// anonymizedRetailerPrefix is how many hex digits of the retailer's hash
+// AnonymizeReceipt keeps.
+const anonymizedRetailerPrefix = 12
+
+// AnonymizeReceipt returns a copy of receipt that is safe to log. The
+// retailer becomes a prefix of its sha256, so receipts from one retailer
+// still group together, and each item description becomes its length in
+// characters. Notes and ImageURL are dropped. Dates, times, amounts and the
+// item structure are kept for debugging. receipt is not modified.
+func AnonymizeReceipt(receipt models.Receipt) models.Receipt {
+	sum := sha256.Sum256([]byte(receipt.Retailer))
+	receipt.Retailer = hex.EncodeToString(sum[:])[:anonymizedRetailerPrefix]
+	items := make([]models.Item, len(receipt.Items))
+	for i, item := range receipt.Items {
+		item.ShortDescription = strconv.Itoa(utf8.RuneCountInString(item.ShortDescription))
+		items[i] = item
+	}
+	receipt.Items = items
+	receipt.Notes = ""
+	receipt.ImageURL = ""
+	return receipt
+}
@@ -0,0 +1,45 @@
+package services
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestAnonymizeReceipt(t *testing.T) {
+	original := targetReceipt()
+	original.Notes = "Paid by Jane Doe"
+	original.ImageURL = "https://example.com/receipts/jane.png"
+	input := targetReceipt()
+	input.Notes, input.ImageURL = original.Notes, original.ImageURL
+
+	anon := AnonymizeReceipt(input)
+
+	if !reflect.DeepEqual(input, original) {
+		t.Error("AnonymizeReceipt modified its input")
+	}
+	encoded, _ := json.Marshal(anon)
+	secrets := []string{original.Retailer, original.Notes, original.ImageURL}
+	for _, item := range original.Items {
+		secrets = append(secrets, strings.TrimSpace(item.ShortDescription))
+	}
+	for _, s := range secrets {
+		if strings.Contains(string(encoded), s) {
+			t.Errorf("anonymized receipt contains %q: %s", s, encoded)
+		}
+	}
+
+	if len(anon.Items) != len(original.Items) {
+		t.Fatalf("items = %d, want %d", len(anon.Items), len(original.Items))
+	}
+	if anon.Items[0].ShortDescription != "17" || anon.Items[0].Price != "6.49" {
+		t.Errorf("first item = %+v, want length 17 and the original price", anon.Items[0])
+	}
+	if anon.Total != original.Total || anon.PurchaseDate != original.PurchaseDate {
+		t.Errorf("total %q date %q, want them preserved", anon.Total, anon.PurchaseDate)
+	}
+	if again := AnonymizeReceipt(targetReceipt()); again.Retailer != anon.Retailer {
+		t.Error("retailer hash is not deterministic")
+	}
+}
@@ -0,0 +1,90 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"receipt-processor/models"
+)
+
+// PartitionValid splits a batch into receipts that pass ValidateReceipt and
+// those that don't, preserving input order within each partition.
+func PartitionValid(receipts []models.Receipt) (valid []models.Receipt, invalid []models.InvalidReceipt) {
+	for i, receipt := range receipts {
+		if err := ValidateReceipt(receipt); err != nil {
+			invalid = append(invalid, models.InvalidReceipt{Index: i, Receipt: receipt, Err: err})
+			continue
+		}
+		valid = append(valid, receipt)
+	}
+	return valid, invalid
+}
+
+// ValidateBatchReport validates every receipt in the batch and summarizes
+// the outcome. Errors without a ValidationError code are counted under
+// "unknown".
+func ValidateBatchReport(receipts []models.Receipt) models.BatchValidationReport {
+	report := models.BatchValidationReport{Total: len(receipts), ByCode: make(map[string]int)}
+	for _, receipt := range receipts {
+		err := ValidateReceipt(receipt)
+		if err == nil {
+			report.Valid++
+			continue
+		}
+		report.Invalid++
+		code := "unknown"
+		var verr *ValidationError
+		if errors.As(err, &verr) {
+			code = verr.Code
+		}
+		report.ByCode[code]++
+	}
+	return report
+}
+
+// BatchDuplicateError reports receipts that appear more than once in a
+// batch. Each group holds the indices of receipts sharing one ID, in order.
+type BatchDuplicateError struct {
+	Groups [][]int
+}
+
+func (e *BatchDuplicateError) Error() string {
+	msgs := make([]string, len(e.Groups))
+	for i, group := range e.Groups {
+		indices := make([]string, len(group))
+		for j, index := range group {
+			indices[j] = strconv.Itoa(index)
+		}
+		msgs[i] = strings.Join(indices, ", ")
+	}
+	return fmt.Sprintf("batch contains duplicate receipts at indices %s", strings.Join(msgs, "; "))
+}
+
+// ValidateBatchUnique checks that no two receipts in the batch have the same
+// ID, returning a *BatchDuplicateError naming the duplicates if any do. IDs
+// are those of the normalized receipts, so receipts Process would treat as
+// duplicates are reported even when their amounts are written differently.
+// The store is not consulted.
+func ValidateBatchUnique(receipts []models.Receipt) error {
+	byID := make(map[string][]int)
+	var order []string
+	for i, receipt := range receipts {
+		id := ComputeReceiptID(receipt)
+		if _, seen := byID[id]; !seen {
+			order = append(order, id)
+		}
+		byID[id] = append(byID[id], i)
+	}
+	var groups [][]int
+	for _, id := range order {
+		if len(byID[id]) > 1 {
+			groups = append(groups, byID[id])
+		}
+	}
+	if groups != nil {
+		return &BatchDuplicateError{Groups: groups}
+	}
+	return nil
+}
@@ -0,0 +1,32 @@
+package services
+
+import (
+	"runtime"
+	"sync"
+
+	"receipt-processor/models"
+)
+
+// CalculatePointsBatch scores each receipt under cfg across a pool of
+// GOMAXPROCS workers, each taking a contiguous share of the slice. points[i]
+// and errs[i] are the result for receipts[i]; errs[i] is nil when receipts[i]
+// scored successfully.
+func CalculatePointsBatch(receipts []models.Receipt, cfg models.RuleConfig) ([]int, []error) {
+	points := make([]int, len(receipts))
+	errs := make([]error, len(receipts))
+
+	workers := min(runtime.GOMAXPROCS(0), len(receipts))
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		start, end := w*len(receipts)/workers, (w+1)*len(receipts)/workers
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := start; i < end; i++ {
+				points[i], errs[i] = CalculatePointsWithConfig(receipts[i], cfg)
+			}
+		}()
+	}
+	wg.Wait()
+	return points, errs
+}
@@ -0,0 +1,63 @@
+package services
+
+import (
+	"testing"
+
+	"receipt-processor/models"
+)
+
+func scoringBatch(n int) []models.Receipt {
+	fixtures := []models.Receipt{targetReceipt(), roundReceipt(), mmReceipt()}
+	receipts := make([]models.Receipt, n)
+	for i := range receipts {
+		receipts[i] = fixtures[i%len(fixtures)]
+	}
+	return receipts
+}
+
+func TestCalculatePointsBatchMatchesSequential(t *testing.T) {
+	receipts := scoringBatch(100)
+	bad := targetReceipt()
+	bad.Total = "abc"
+	receipts[41] = bad
+	cfg := models.DefaultRuleConfig()
+
+	points, errs := CalculatePointsBatch(receipts, cfg)
+	if len(points) != len(receipts) || len(errs) != len(receipts) {
+		t.Fatalf("got %d points and %d errors for %d receipts", len(points), len(errs), len(receipts))
+	}
+	for i, r := range receipts {
+		want, wantErr := CalculatePointsWithConfig(r, cfg)
+		if points[i] != want || (errs[i] == nil) != (wantErr == nil) {
+			t.Errorf("receipt %d: got %d, %v; want %d, %v", i, points[i], errs[i], want, wantErr)
+		}
+	}
+	if errs[41] == nil {
+		t.Error("expected an error for the receipt with an invalid total")
+	}
+}
+
+func TestCalculatePointsBatchEmpty(t *testing.T) {
+	points, errs := CalculatePointsBatch(nil, models.DefaultRuleConfig())
+	if len(points) != 0 || len(errs) != 0 {
+		t.Errorf("got %v, %v for an empty batch", points, errs)
+	}
+}
+
+func BenchmarkCalculatePointsBatch(b *testing.B) {
+	receipts := scoringBatch(10000)
+	cfg := models.DefaultRuleConfig()
+	for i := 0; i < b.N; i++ {
+		CalculatePointsBatch(receipts, cfg)
+	}
+}
+
+func BenchmarkCalculatePointsSequential(b *testing.B) {
+	receipts := scoringBatch(10000)
+	cfg := models.DefaultRuleConfig()
+	for i := 0; i < b.N; i++ {
+		for _, r := range receipts {
+			CalculatePointsWithConfig(r, cfg)
+		}
+	}
+}
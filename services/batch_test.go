@@ -0,0 +1,96 @@
+package services
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"receipt-processor/models"
+)
+
+func TestPartitionValid(t *testing.T) {
+	noItems := targetReceipt()
+	noItems.Items = nil
+	badTotal := roundReceipt()
+	badTotal.Total = "9"
+
+	batch := []models.Receipt{targetReceipt(), noItems, roundReceipt(), badTotal}
+	valid, invalid := PartitionValid(batch)
+
+	if len(valid) != 2 || valid[0].Retailer != "Target" || valid[1].Retailer != "Corner Shop" {
+		t.Errorf("valid = %+v, want Target then Corner Shop", valid)
+	}
+	if len(invalid) != 2 {
+		t.Fatalf("invalid = %+v, want 2 entries", invalid)
+	}
+	for i, want := range []struct {
+		index int
+		code  string
+	}{{1, CodeNoItems}, {3, CodeInvalidFormat}} {
+		if invalid[i].Index != want.index {
+			t.Errorf("invalid[%d].Index = %d, want %d", i, invalid[i].Index, want.index)
+		}
+		assertValidationCode(t, invalid[i].Err, want.code)
+	}
+}
+
+func TestPartitionValidEmpty(t *testing.T) {
+	valid, invalid := PartitionValid(nil)
+	if valid != nil || invalid != nil {
+		t.Errorf("PartitionValid(nil) = %v, %v", valid, invalid)
+	}
+}
+
+func TestValidateBatchUnique(t *testing.T) {
+	if err := ValidateBatchUnique([]models.Receipt{targetReceipt(), roundReceipt(), mmReceipt()}); err != nil {
+		t.Errorf("clean batch: %v", err)
+	}
+
+	err := ValidateBatchUnique([]models.Receipt{targetReceipt(), roundReceipt(), targetReceipt()})
+	var dupErr *BatchDuplicateError
+	if !errors.As(err, &dupErr) {
+		t.Fatalf("err = %v, want *BatchDuplicateError", err)
+	}
+	if want := [][]int{{0, 2}}; !reflect.DeepEqual(dupErr.Groups, want) {
+		t.Errorf("Groups = %v, want %v", dupErr.Groups, want)
+	}
+	if want := "batch contains duplicate receipts at indices 0, 2"; err.Error() != want {
+		t.Errorf("Error() = %q, want %q", err.Error(), want)
+	}
+}
+
+func TestValidateBatchUniqueNonCanonical(t *testing.T) {
+	padded := targetReceipt()
+	padded.Total = "035.35"
+	err := ValidateBatchUnique([]models.Receipt{targetReceipt(), padded})
+	var dupErr *BatchDuplicateError
+	if !errors.As(err, &dupErr) {
+		t.Fatalf("err = %v, want *BatchDuplicateError", err)
+	}
+	if want := [][]int{{0, 1}}; !reflect.DeepEqual(dupErr.Groups, want) {
+		t.Errorf("Groups = %v, want %v", dupErr.Groups, want)
+	}
+}
+
+func TestValidateBatchReport(t *testing.T) {
+	noItems := targetReceipt()
+	noItems.Items = nil
+	badTotal := roundReceipt()
+	badTotal.Total = "9"
+	badDate := mmReceipt()
+	badDate.PurchaseDate = "2022/03/20"
+	noRetailer := targetReceipt()
+	noRetailer.Retailer = ""
+
+	batch := []models.Receipt{targetReceipt(), noItems, roundReceipt(), badTotal, badDate, noRetailer}
+	got := ValidateBatchReport(batch)
+	want := models.BatchValidationReport{
+		Total:   6,
+		Valid:   2,
+		Invalid: 4,
+		ByCode:  map[string]int{CodeNoItems: 1, CodeInvalidFormat: 2, CodeMissingField: 1},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ValidateBatchReport = %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,145 @@
+package services
+
+import (
+	"fmt"
+	"time"
+
+	"receipt-processor/models"
+)
+
+// Names of the breakdown entries that adjust the summed rule points.
+const (
+	basePointsRule = "base_points"
+	roundingRule   = "final_rounding"
+	maxPointsRule  = "max_points"
+	lateRule       = "late_submission"
+)
+
+// CalculatePointsWithBreakdown scores a receipt and reports each built-in
+// enabled rule's contribution in scoring order. Entries for BasePoints, a
+// RoundFinalTo adjustment and a MaxPoints reduction follow when they change
+// the score, so the contributions always sum to the total.
+func CalculatePointsWithBreakdown(receipt models.Receipt, cfg models.RuleConfig) (int, []models.Contribution, error) {
+	total := 0
+	breakdown := make([]models.Contribution, 0, len(builtinRules)+3)
+	for _, r := range builtinRules {
+		if !ruleEnabled(r.name, cfg) {
+			continue
+		}
+		p, err := r.apply(receipt, cfg)
+		if err != nil {
+			return 0, nil, err
+		}
+		total += p
+		breakdown = append(breakdown, models.Contribution{Rule: r.name, Points: p})
+	}
+	if cfg.BasePoints != 0 {
+		breakdown = append(breakdown, models.Contribution{Rule: basePointsRule, Points: cfg.BasePoints})
+		total += cfg.BasePoints
+	}
+	if rounded := roundFinal(total, cfg); rounded != total {
+		breakdown = append(breakdown, models.Contribution{Rule: roundingRule, Points: rounded - total})
+		total = rounded
+	}
+	if capped := capPoints(total, cfg); capped != total {
+		breakdown = append(breakdown, models.Contribution{Rule: maxPointsRule, Points: capped - total})
+		total = capped
+	}
+	return total, breakdown, nil
+}
+
+// FiredRules returns the names of the built-in rules that awarded a receipt
+// positive points, in scoring order. BasePoints and RoundFinalTo
+// adjustments are not rules and are left out.
+func FiredRules(receipt models.Receipt, cfg models.RuleConfig) ([]string, error) {
+	_, breakdown, err := CalculatePointsWithBreakdown(receipt, cfg)
+	if err != nil {
+		return nil, err
+	}
+	var fired []string
+	for _, c := range breakdown {
+		if c.Points > 0 && isBuiltinRule(c.Rule) {
+			fired = append(fired, c.Rule)
+		}
+	}
+	return fired, nil
+}
+
+// WouldScoreZero reports whether no built-in rule awards the receipt
+// positive points under cfg. BasePoints does not count as a rule, so a
+// receipt may be flagged even though its final score is positive.
+func WouldScoreZero(receipt models.Receipt, cfg models.RuleConfig) (bool, error) {
+	for _, r := range builtinRules {
+		p, err := r.apply(receipt, cfg)
+		if err != nil {
+			return false, err
+		}
+		if p > 0 {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// PointsToTarget returns how many more points the receipt would need under
+// cfg to reach target, or zero if it already does.
+func PointsToTarget(receipt models.Receipt, target int, cfg models.RuleConfig) (short int, err error) {
+	points, err := CalculatePointsWithConfig(receipt, cfg)
+	if err != nil {
+		return 0, err
+	}
+	return max(0, target-points), nil
+}
+
+// ScoreReceipt scores a receipt and returns the points together with their
+// breakdown, the version of cfg and, if cfg.PointsLifetime is set, when
+// they expire.
+func ScoreReceipt(receipt models.Receipt, cfg models.RuleConfig) (models.ScoreResult, error) {
+	total, breakdown, err := CalculatePointsWithBreakdown(receipt, cfg)
+	if err != nil {
+		return models.ScoreResult{}, err
+	}
+	result := models.ScoreResult{
+		Points:            total,
+		Breakdown:         breakdown,
+		RuleConfigVersion: cfg.Version,
+	}
+	if cfg.PointsLifetime > 0 {
+		expiresAt, err := PointsExpiry(receipt, cfg.PointsLifetime)
+		if err != nil {
+			return models.ScoreResult{}, err
+		}
+		result.ExpiresAt = &expiresAt
+	}
+	return result, nil
+}
+
+// PointsExpiry returns when points earned by the receipt lapse: lifetime
+// after the start of its purchase date in UTC.
+func PointsExpiry(receipt models.Receipt, lifetime time.Duration) (time.Time, error) {
+	date, err := time.Parse(dateLayout, receipt.PurchaseDate)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid purchase date: %w", err)
+	}
+	return date.Add(lifetime), nil
+}
+
+// IsolatedRulePoints returns, for each enabled built-in rule, the points
+// that rule alone would award the receipt. Rules are scored without regard
+// to one another, so MutuallyExclusiveTotalRules is ignored, and
+// RoundFinalTo is not applied.
+func IsolatedRulePoints(receipt models.Receipt, cfg models.RuleConfig) (map[string]int, error) {
+	cfg.MutuallyExclusiveTotalRules = false
+	points := make(map[string]int, len(builtinRules))
+	for _, r := range builtinRules {
+		if !ruleEnabled(r.name, cfg) {
+			continue
+		}
+		p, err := r.apply(receipt, cfg)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", r.name, err)
+		}
+		points[r.name] = p
+	}
+	return points, nil
+}
@@ -0,0 +1,288 @@
+package services
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"receipt-processor/models"
+)
+
+func TestCalculatePointsWithBreakdown(t *testing.T) {
+	total, breakdown, err := CalculatePointsWithBreakdown(targetReceipt(), models.DefaultRuleConfig())
+	if err != nil {
+		t.Fatalf("CalculatePointsWithBreakdown: %v", err)
+	}
+	want := []models.Contribution{
+		{Rule: "retailer_name", Points: 6},
+		{Rule: "round_dollar", Points: 0},
+		{Rule: "quarter_multiple", Points: 0},
+		{Rule: "item_pairs", Points: 10},
+		{Rule: "description_length", Points: 6},
+		{Rule: "odd_day", Points: 6},
+		{Rule: "even_day", Points: 0},
+		{Rule: "time_window", Points: 0},
+	}
+	if !reflect.DeepEqual(breakdown, want) {
+		t.Errorf("breakdown = %+v, want %+v", breakdown, want)
+	}
+	if total != 28 {
+		t.Errorf("total = %d, want 28", total)
+	}
+}
+
+func TestFiredRules(t *testing.T) {
+	receipt := models.Receipt{
+		Retailer:     "Target",
+		PurchaseDate: "2022-01-01",
+		PurchaseTime: "13:01",
+		Items:        []models.Item{{ShortDescription: "Pizza", Price: "10.00"}},
+		Total:        "10.00",
+	}
+
+	got, err := FiredRules(receipt, models.DefaultRuleConfig())
+	if err != nil {
+		t.Fatalf("FiredRules: %v", err)
+	}
+	want := []string{"retailer_name", "round_dollar", "quarter_multiple", "odd_day"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("fired = %v, want %v", got, want)
+	}
+}
+
+func TestFiredRulesOmitsAdjustments(t *testing.T) {
+	receipt := models.Receipt{
+		Retailer:     "Target",
+		PurchaseDate: "2022-01-01",
+		PurchaseTime: "10:00",
+		Items:        []models.Item{{ShortDescription: "Pizza", Price: "10.49"}},
+		Total:        "10.49",
+	}
+	cfg := models.DefaultRuleConfig()
+	cfg.BasePoints = 5
+	cfg.RoundFinalTo = 10
+	cfg.RoundingMode = models.RoundUp
+
+	got, err := FiredRules(receipt, cfg)
+	if err != nil {
+		t.Fatalf("FiredRules: %v", err)
+	}
+	want := []string{"retailer_name", "odd_day"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("fired = %v, want %v", got, want)
+	}
+}
+
+func TestScoreReceipt(t *testing.T) {
+	promo := models.DefaultRuleConfig()
+	promo.Version = "promo-2022-06"
+	promo.OddDayPoints = 20
+
+	tests := []struct {
+		cfg    models.RuleConfig
+		points int
+	}{
+		{models.DefaultRuleConfig(), 28},
+		{promo, 42},
+	}
+
+	for _, tt := range tests {
+		result, err := ScoreReceipt(targetReceipt(), tt.cfg)
+		if err != nil {
+			t.Fatalf("ScoreReceipt: %v", err)
+		}
+		if result.RuleConfigVersion != tt.cfg.Version {
+			t.Errorf("version = %q, want %q", result.RuleConfigVersion, tt.cfg.Version)
+		}
+		if result.Points != tt.points {
+			t.Errorf("%s: points = %d, want %d", tt.cfg.Version, result.Points, tt.points)
+		}
+		sum := 0
+		for _, c := range result.Breakdown {
+			sum += c.Points
+		}
+		if sum != result.Points {
+			t.Errorf("%s: breakdown sums to %d, points = %d", tt.cfg.Version, sum, result.Points)
+		}
+	}
+}
+
+func TestBreakdownRecordsRounding(t *testing.T) {
+	cfg := models.DefaultRuleConfig()
+	cfg.RoundFinalTo = 5
+	cfg.RoundingMode = models.RoundDown
+
+	total, breakdown, err := CalculatePointsWithBreakdown(targetReceipt(), cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	last := breakdown[len(breakdown)-1]
+	if total != 25 || last != (models.Contribution{Rule: "final_rounding", Points: -3}) {
+		t.Errorf("total = %d, last entry = %+v; want 25 and final_rounding -3", total, last)
+	}
+}
+
+func TestIsolatedRulePoints(t *testing.T) {
+	receipt := models.Receipt{
+		Retailer:     "Shop & Go",  // 6 alphanumeric characters
+		PurchaseDate: "2022-05-07", // odd day
+		PurchaseTime: "15:10",      // inside 14:00-16:00
+		Total:        "20.00",      // round dollar and multiple of 0.25
+		Items: []models.Item{
+			{ShortDescription: "Bread", Price: "4.00"},
+			{ShortDescription: "Cheddar", Price: "6.00"},
+			{ShortDescription: "Tomato Soup", Price: "10.00"}, // length 11: not eligible
+			{ShortDescription: "Ice Cream", Price: "10.00"},   // length 9: ceil(2.0) = 2
+		},
+	}
+	cfg := models.DefaultRuleConfig()
+	cfg.MutuallyExclusiveTotalRules = true
+	cfg.RoundFinalTo = 10
+
+	got, err := IsolatedRulePoints(receipt, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]int{
+		"retailer_name":      6,
+		"round_dollar":       50,
+		"quarter_multiple":   25,
+		"item_pairs":         10,
+		"description_length": 2,
+		"odd_day":            6,
+		"even_day":           0,
+		"time_window":        10,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("IsolatedRulePoints = %v, want %v", got, want)
+	}
+}
+
+func TestWouldScoreZero(t *testing.T) {
+	zero := models.Receipt{
+		Retailer:     "&-&",        // no alphanumerics
+		PurchaseDate: "2022-01-02", // even day
+		PurchaseTime: "09:15",      // outside the window
+		Items:        []models.Item{{ShortDescription: "Milk", Price: "3.49"}},
+		Total:        "3.49", // neither round nor a quarter multiple
+	}
+	cfg := models.DefaultRuleConfig()
+	cfg.BasePoints = 5
+
+	tests := []struct {
+		name    string
+		receipt models.Receipt
+		want    bool
+	}{
+		{"zero-scoring", zero, true},
+		{"target", targetReceipt(), false},
+	}
+	for _, tt := range tests {
+		got, err := WouldScoreZero(tt.receipt, cfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != tt.want {
+			t.Errorf("%s: WouldScoreZero = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestPointsToTarget(t *testing.T) {
+	tests := []struct {
+		target int
+		want   int
+	}{
+		{33, 5}, // target receipt scores 28
+		{28, 0},
+		{20, 0},
+	}
+	for _, tt := range tests {
+		got, err := PointsToTarget(targetReceipt(), tt.target, models.DefaultRuleConfig())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != tt.want {
+			t.Errorf("target %d: short = %d, want %d", tt.target, got, tt.want)
+		}
+	}
+}
+
+func TestScoreReceiptExpiry(t *testing.T) {
+	result, err := ScoreReceipt(targetReceipt(), models.DefaultRuleConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.ExpiresAt != nil {
+		t.Errorf("ExpiresAt = %v without a lifetime, want nil", result.ExpiresAt)
+	}
+
+	cfg := models.DefaultRuleConfig()
+	cfg.PointsLifetime = 90 * 24 * time.Hour
+	result, err = ScoreReceipt(targetReceipt(), cfg) // purchased 2022-01-01
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := time.Date(2022, time.April, 1, 0, 0, 0, 0, time.UTC)
+	if result.ExpiresAt == nil || !result.ExpiresAt.Equal(want) {
+		t.Errorf("ExpiresAt = %v, want %v", result.ExpiresAt, want)
+	}
+}
+
+func TestPointsExpiry(t *testing.T) {
+	receipt := targetReceipt()
+	receipt.PurchaseDate = "2024-02-28"
+	got, err := PointsExpiry(receipt, 48*time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC); !got.Equal(want) {
+		t.Errorf("expiry = %v, want %v", got, want)
+	}
+
+	receipt.PurchaseDate = "2024/02/28"
+	if _, err := PointsExpiry(receipt, time.Hour); err == nil {
+		t.Error("unparseable purchase date accepted")
+	}
+}
+
+func TestEnabledRules(t *testing.T) {
+	cfg := models.DefaultRuleConfig()
+	cfg.EnabledRules = map[string]bool{"time_window": false, "odd_day": true}
+	receipt := roundReceipt() // 105 points, 10 of them from the time window
+
+	total, breakdown, err := CalculatePointsWithBreakdown(receipt, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total != 95 {
+		t.Errorf("total = %d, want 95", total)
+	}
+	for _, c := range breakdown {
+		if c.Rule == "time_window" {
+			t.Errorf("disabled rule in breakdown: %+v", breakdown)
+		}
+	}
+	if len(breakdown) != len(builtinRules)-1 {
+		t.Errorf("breakdown has %d entries, want %d", len(breakdown), len(builtinRules)-1)
+	}
+	if points, _ := CalculatePointsWithConfig(receipt, cfg); points != 95 {
+		t.Errorf("CalculatePointsWithConfig = %d, want 95", points)
+	}
+	isolated, _ := IsolatedRulePoints(receipt, cfg)
+	if _, ok := isolated["time_window"]; ok {
+		t.Errorf("disabled rule in isolated points: %v", isolated)
+	}
+
+	acc := NewScoreAccumulator(cfg)
+	acc.SetRetailer(receipt.Retailer)
+	acc.SetDate(receipt.PurchaseDate)
+	acc.SetTime(receipt.PurchaseTime)
+	acc.SetTotal(receipt.Total)
+	for _, item := range receipt.Items {
+		acc.AddItem(item)
+	}
+	if got := acc.Points(); got != 95 {
+		t.Errorf("accumulator points = %d, want 95", got)
+	}
+}
@@ -0,0 +1,14 @@
+package services
+
+import "time"
+
+// Clock supplies the current time. Tests inject a fixed clock.
+type Clock func() time.Time
+
+// SystemClock reads the wall clock.
+var SystemClock Clock = time.Now
+
+// FixedClock returns a Clock that always reports t.
+func FixedClock(t time.Time) Clock {
+	return func() time.Time { return t }
+}
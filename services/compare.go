@@ -0,0 +1,51 @@
+package services
+
+import (
+	"fmt"
+
+	"receipt-processor/models"
+)
+
+// CompareConfigs scores a receipt under two rule configs and returns both
+// scores along with delta = pointsB - pointsA.
+func CompareConfigs(receipt models.Receipt, a, b models.RuleConfig) (pointsA, pointsB int, delta int, err error) {
+	pointsA, err = CalculatePointsWithConfig(receipt, a)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	pointsB, err = CalculatePointsWithConfig(receipt, b)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	return pointsA, pointsB, pointsB - pointsA, nil
+}
+
+// ScoreUnderConfigs scores a receipt under each named config, returning the
+// points keyed by the same names. If any config fails to score the receipt,
+// an error naming it is returned instead.
+func ScoreUnderConfigs(receipt models.Receipt, configs map[string]models.RuleConfig) (map[string]int, error) {
+	scores := make(map[string]int, len(configs))
+	for name, cfg := range configs {
+		points, err := CalculatePointsWithConfig(receipt, cfg)
+		if err != nil {
+			return nil, fmt.Errorf("config %q: %w", name, err)
+		}
+		scores[name] = points
+	}
+	return scores, nil
+}
+
+// RescorePreview returns the points stored for the receipt with the given
+// ID alongside the points it would earn under cfg. The store is not
+// modified.
+func RescorePreview(id string, store ReceiptStore, cfg models.RuleConfig) (oldPoints, newPoints int, err error) {
+	entry, err := store.Get(id)
+	if err != nil {
+		return 0, 0, err
+	}
+	newPoints, err = CalculatePointsWithConfig(entry.Receipt, cfg)
+	if err != nil {
+		return 0, 0, err
+	}
+	return entry.Points, newPoints, nil
+}
@@ -0,0 +1,98 @@
+package services
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"receipt-processor/models"
+)
+
+func TestCompareConfigs(t *testing.T) {
+	a := models.DefaultRuleConfig()
+	b := models.DefaultRuleConfig()
+	b.RoundDollarPoints = 80
+
+	pointsA, pointsB, delta, err := CompareConfigs(roundReceipt(), a, b)
+	if err != nil {
+		t.Fatalf("CompareConfigs: %v", err)
+	}
+	if pointsA != 105 || pointsB != 135 {
+		t.Errorf("points = (%d, %d), want (105, 135)", pointsA, pointsB)
+	}
+	if delta != 30 {
+		t.Errorf("delta = %d, want 30", delta)
+	}
+}
+
+func TestCompareConfigsUnaffectedReceipt(t *testing.T) {
+	a := models.DefaultRuleConfig()
+	b := models.DefaultRuleConfig()
+	b.RoundDollarPoints = 80
+
+	_, _, delta, err := CompareConfigs(targetReceipt(), a, b)
+	if err != nil {
+		t.Fatalf("CompareConfigs: %v", err)
+	}
+	if delta != 0 {
+		t.Errorf("delta = %d, want 0 for a non-round total", delta)
+	}
+}
+
+func TestScoreUnderConfigs(t *testing.T) {
+	generous := models.DefaultRuleConfig()
+	generous.RoundDollarPoints = 80
+	generous.BasePoints = 10
+
+	scores, err := ScoreUnderConfigs(roundReceipt(), map[string]models.RuleConfig{
+		"control":  models.DefaultRuleConfig(),
+		"generous": generous,
+	})
+	if err != nil {
+		t.Fatalf("ScoreUnderConfigs: %v", err)
+	}
+	want := map[string]int{"control": 105, "generous": 145}
+	if !reflect.DeepEqual(scores, want) {
+		t.Errorf("scores = %v, want %v", scores, want)
+	}
+}
+
+func TestScoreUnderConfigsError(t *testing.T) {
+	receipt := roundReceipt()
+	receipt.Total = "nine"
+	if _, err := ScoreUnderConfigs(receipt, map[string]models.RuleConfig{"control": models.DefaultRuleConfig()}); err == nil {
+		t.Error("expected an error for an unscoreable receipt")
+	}
+}
+
+func TestRescorePreview(t *testing.T) {
+	store := NewMapStore()
+	id, err := ProcessReceipt(roundReceipt(), store)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := models.DefaultRuleConfig()
+	cfg.RoundDollarPoints = 80
+
+	oldPoints, newPoints, err := RescorePreview(id, store, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if oldPoints != 105 || newPoints != 135 {
+		t.Errorf("preview = (%d, %d), want (105, 135)", oldPoints, newPoints)
+	}
+	entry, err := store.Get(id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if entry.Points != 105 {
+		t.Errorf("stored points = %d after preview, want 105", entry.Points)
+	}
+}
+
+func TestRescorePreviewNotFound(t *testing.T) {
+	_, _, err := RescorePreview("missing", NewMapStore(), models.DefaultRuleConfig())
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("err = %v, want ErrNotFound", err)
+	}
+}
@@ -0,0 +1,51 @@
+package services
+
+import (
+	"sync"
+
+	"receipt-processor/models"
+)
+
+// ConcurrentStore makes any ReceiptStore safe for concurrent use. Reads
+// share a read lock, so they don't block each other; writes are exclusive.
+type ConcurrentStore struct {
+	mu    sync.RWMutex
+	inner ReceiptStore
+}
+
+// NewConcurrentStore wraps inner, which must not be used directly afterwards.
+func NewConcurrentStore(inner ReceiptStore) *ConcurrentStore {
+	return &ConcurrentStore{inner: inner}
+}
+
+func (s *ConcurrentStore) Get(id string) (models.StoredReceipt, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.inner.Get(id)
+}
+
+func (s *ConcurrentStore) Set(id string, entry models.StoredReceipt) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.inner.Set(id, entry)
+}
+
+func (s *ConcurrentStore) Has(id string) (bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.inner.Has(id)
+}
+
+func (s *ConcurrentStore) Delete(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.inner.Delete(id)
+}
+
+// Range holds the read lock for the whole iteration, so fn must not write
+// to the store.
+func (s *ConcurrentStore) Range(fn func(id string, entry models.StoredReceipt) bool) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.inner.Range(fn)
+}
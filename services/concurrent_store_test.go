@@ -0,0 +1,51 @@
+package services
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"receipt-processor/models"
+)
+
+// Run with -race to check the wrapper serializes access to MapStore.
+func TestConcurrentStore(t *testing.T) {
+	store := NewConcurrentStore(NewMapStore())
+	const workers, perWorker = 8, 200
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < perWorker; i++ {
+				id := fmt.Sprintf("%d-%d", w, i)
+				if err := store.Set(id, models.StoredReceipt{Points: i}); err != nil {
+					t.Error(err)
+					return
+				}
+				if _, err := store.Get(id); err != nil {
+					t.Error(err)
+					return
+				}
+				store.Has(fmt.Sprintf("%d-%d", (w+1)%workers, i))
+				if i%2 == 1 {
+					store.Delete(id)
+				}
+			}
+		}(w)
+	}
+	wg.Wait()
+
+	count := 0
+	store.Range(func(id string, entry models.StoredReceipt) bool {
+		count++
+		if entry.Points%2 != 0 {
+			t.Errorf("%s: odd entry survived delete", id)
+		}
+		return true
+	})
+	if want := workers * perWorker / 2; count != want {
+		t.Errorf("final size = %d, want %d", count, want)
+	}
+}
@@ -0,0 +1,50 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+
+	"receipt-processor/models"
+)
+
+// Correct applies patch to a copy of the receipt stored under oldID and
+// processes the result as a new submission. On success the corrected
+// receipt's ID and points are returned, and the old entry is removed unless
+// keepOld is set. If the corrected receipt is invalid or already stored
+// under another ID, the old entry is left in place. A patch that leaves the
+// ID unchanged is an error and the stored entry is not modified.
+func (p *Processor) Correct(oldID string, patch func(*models.Receipt), keepOld bool) (newID string, points int, err error) {
+	entry, err := p.Store.Get(oldID)
+	if err != nil {
+		return "", 0, err
+	}
+	receipt := entry.Receipt
+	receipt.Items = append([]models.Item(nil), receipt.Items...)
+	receipt.Tags = append([]string(nil), receipt.Tags...)
+	patch(&receipt)
+
+	newID, err = p.Process(receipt)
+	if newID == oldID && (err == nil || errors.Is(err, ErrDuplicateReceipt)) {
+		return oldID, entry.Points, fmt.Errorf("correcting %s: patch does not change the receipt's ID", oldID)
+	}
+	if err != nil {
+		return newID, 0, err
+	}
+	stored, err := p.Store.Get(newID)
+	if err != nil {
+		return newID, 0, err
+	}
+	if keepOld {
+		return newID, stored.Points, nil
+	}
+	if err := p.Store.Delete(oldID); err != nil {
+		return newID, stored.Points, err
+	}
+	return newID, stored.Points, nil
+}
+
+// CorrectReceipt corrects the receipt stored under oldID with the default
+// Processor for store. See Processor.Correct.
+func CorrectReceipt(oldID string, store ReceiptStore, patch func(*models.Receipt), keepOld bool) (newID string, points int, err error) {
+	return NewProcessor(store).Correct(oldID, patch, keepOld)
+}
@@ -0,0 +1,102 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"receipt-processor/models"
+)
+
+func TestCorrectReceipt(t *testing.T) {
+	store := NewMapStore()
+	typo := roundReceipt()
+	typo.Total = "9.01"
+	oldID, err := ProcessReceipt(typo, store)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	newID, points, err := CorrectReceipt(oldID, store, func(r *models.Receipt) {
+		r.Total = "9.00"
+	}, false)
+	if err != nil {
+		t.Fatalf("CorrectReceipt: %v", err)
+	}
+	if newID == oldID {
+		t.Error("corrected receipt kept its old ID")
+	}
+	if points != 105 {
+		t.Errorf("points = %d, want 105 for the corrected total", points)
+	}
+	if _, err := store.Get(oldID); !errors.Is(err, ErrNotFound) {
+		t.Errorf("old entry: err = %v, want ErrNotFound", err)
+	}
+	entry, err := store.Get(newID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if entry.Receipt.Total != "9.00" || entry.Points != 105 {
+		t.Errorf("stored %q with %d points", entry.Receipt.Total, entry.Points)
+	}
+}
+
+func TestCorrectReceiptKeepOld(t *testing.T) {
+	store := NewMapStore()
+	typo := roundReceipt()
+	typo.Total = "9.01"
+	oldID, err := ProcessReceipt(typo, store)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	newID, points, err := CorrectReceipt(oldID, store, func(r *models.Receipt) {
+		r.Total = "9.00"
+	}, true)
+	if err != nil {
+		t.Fatalf("CorrectReceipt: %v", err)
+	}
+	if points != 105 {
+		t.Errorf("points = %d, want 105 for the corrected total", points)
+	}
+	for _, id := range []string{oldID, newID} {
+		if ok, _ := store.Has(id); !ok {
+			t.Errorf("entry %s missing, want both old and corrected kept", id)
+		}
+	}
+}
+
+func TestCorrectReceiptInvalidPatchKeepsOld(t *testing.T) {
+	store := NewMapStore()
+	oldID, err := ProcessReceipt(targetReceipt(), store)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _, err = CorrectReceipt(oldID, store, func(r *models.Receipt) {
+		r.Total = "35"
+	}, false)
+	assertValidationCode(t, err, CodeInvalidFormat)
+	if ok, _ := store.Has(oldID); !ok {
+		t.Error("old entry was removed after a failed correction")
+	}
+}
+
+func TestCorrectReceiptNoChange(t *testing.T) {
+	store := NewMapStore()
+	oldID, err := ProcessReceipt(targetReceipt(), store)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := CorrectReceipt(oldID, store, func(r *models.Receipt) {}, false); err == nil {
+		t.Error("expected an error for a patch that changes nothing")
+	}
+	if ok, _ := store.Has(oldID); !ok {
+		t.Error("entry was removed by a no-op correction")
+	}
+}
+
+func TestCorrectReceiptMissing(t *testing.T) {
+	_, _, err := CorrectReceipt("missing", NewMapStore(), func(r *models.Receipt) {}, false)
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("err = %v, want ErrNotFound", err)
+	}
+}
@@ -0,0 +1,31 @@
+package services
+
+import "receipt-processor/models"
+
+// LenStore is implemented by stores that can report their size without
+// iterating. CountReceipts uses it when available.
+type LenStore interface {
+	Len() int
+}
+
+// Len returns the number of stored entries.
+func (s *MapStore) Len() int {
+	return len(s.entries)
+}
+
+// CountReceipts returns the number of receipts in store, using Len if the
+// store implements LenStore and counting with Range otherwise.
+func CountReceipts(store ReceiptStore) (int, error) {
+	if s, ok := store.(LenStore); ok {
+		return s.Len(), nil
+	}
+	n := 0
+	err := store.Range(func(string, models.StoredReceipt) bool {
+		n++
+		return true
+	})
+	if err != nil {
+		return 0, err
+	}
+	return n, nil
+}
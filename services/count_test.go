@@ -0,0 +1,43 @@
+package services
+
+import (
+	"testing"
+
+	"receipt-processor/models"
+)
+
+func TestCountReceipts(t *testing.T) {
+	stores := map[string]ReceiptStore{
+		"map":        NewMapStore(),
+		"concurrent": NewConcurrentStore(NewMapStore()),
+	}
+	for name, store := range stores {
+		assertCount := func(want int) {
+			t.Helper()
+			got, err := CountReceipts(store)
+			if err != nil {
+				t.Fatalf("%s: CountReceipts: %v", name, err)
+			}
+			if got != want {
+				t.Errorf("%s: count = %d, want %d", name, got, want)
+			}
+		}
+
+		assertCount(0)
+		var ids []string
+		for i, r := range []models.Receipt{targetReceipt(), roundReceipt(), mmReceipt()} {
+			id, err := ProcessReceipt(r, store)
+			if err != nil {
+				t.Fatalf("%s: ProcessReceipt: %v", name, err)
+			}
+			ids = append(ids, id)
+			assertCount(i + 1)
+		}
+		for i, id := range ids {
+			if err := store.Delete(id); err != nil {
+				t.Fatal(err)
+			}
+			assertCount(len(ids) - i - 1)
+		}
+	}
+}
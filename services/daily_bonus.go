@@ -0,0 +1,62 @@
+package services
+
+import (
+	"strings"
+
+	"receipt-processor/models"
+)
+
+// withholdRepeatTotalBonuses returns cfg with the round-dollar and
+// quarter-multiple rules gated off if store holds a receipt, other than the
+// one under id, from the same retailer on the same purchase date whose total
+// earned either bonus under cfg, gates and EnabledRules included. Retailers
+// are compared case-insensitively after trimming.
+func withholdRepeatTotalBonuses(id string, receipt models.Receipt, store ReceiptStore, cfg models.RuleConfig) (models.RuleConfig, error) {
+	retailer := strings.TrimSpace(receipt.Retailer)
+	earned := false
+	var scoreErr error
+	err := store.Range(func(otherID string, entry models.StoredReceipt) bool {
+		other := entry.Receipt
+		if otherID == id || other.PurchaseDate != receipt.PurchaseDate || !strings.EqualFold(strings.TrimSpace(other.Retailer), retailer) {
+			return true
+		}
+		earned, scoreErr = earnedTotalBonus(other, cfg)
+		return scoreErr == nil && !earned
+	})
+	if err != nil {
+		return cfg, err
+	}
+	if scoreErr != nil {
+		return cfg, scoreErr
+	}
+	if !earned {
+		return cfg, nil
+	}
+	gates := make(map[string]models.ReceiptPredicate, len(cfg.RuleGates)+2)
+	for name, gate := range cfg.RuleGates {
+		gates[name] = gate
+	}
+	never := func(models.Receipt) bool { return false }
+	gates["round_dollar"] = never
+	gates["quarter_multiple"] = never
+	cfg.RuleGates = gates
+	return cfg, nil
+}
+
+// earnedTotalBonus reports whether the round-dollar or quarter-multiple rule
+// awards receipt points under cfg.
+func earnedTotalBonus(receipt models.Receipt, cfg models.RuleConfig) (bool, error) {
+	for _, r := range builtinRules {
+		if r.name != "round_dollar" && r.name != "quarter_multiple" {
+			continue
+		}
+		p, err := r.apply(receipt, cfg)
+		if err != nil {
+			return false, err
+		}
+		if p > 0 {
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,100 @@
+package services
+
+import (
+	"testing"
+
+	"receipt-processor/models"
+)
+
+func TestTotalBonusOncePerDay(t *testing.T) {
+	p := NewProcessor(NewMapStore())
+	p.TotalBonusOncePerDay = true
+
+	first := roundReceipt() // 9.00 earns 50 + 25 of its 105 points
+	second := roundReceipt()
+	second.Items = second.Items[:2]
+	second.Total = "4.50" // also a quarter multiple
+	second.Retailer = "corner shop "
+	otherDay := roundReceipt()
+	otherDay.PurchaseDate = "2022-03-21"
+	otherRetailer := roundReceipt()
+	otherRetailer.Retailer = "Corner Shop Annex"
+
+	tests := []struct {
+		name    string
+		receipt models.Receipt
+		want    int
+	}{
+		{"first of the day", first, 105},
+		{"second same retailer and day", second, 10 + 5 + 10}, // retailer, one pair, time window
+		{"another day", otherDay, 105 + 6},                    // odd day
+		{"another retailer", otherRetailer, 105 + 5},
+	}
+	for _, tt := range tests {
+		id, err := p.Process(tt.receipt)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		entry, _ := p.Store.Get(id)
+		if entry.Points != tt.want {
+			t.Errorf("%s: points = %d, want %d", tt.name, entry.Points, tt.want)
+		}
+	}
+}
+
+func TestTotalBonusOncePerDayNeedsAnEarningReceipt(t *testing.T) {
+	p := NewProcessor(NewMapStore())
+	p.TotalBonusOncePerDay = true
+
+	noBonus := roundReceipt()
+	noBonus.Items = noBonus.Items[:1]
+	noBonus.Total = "2.26"
+	if _, err := p.Process(noBonus); err != nil {
+		t.Fatal(err)
+	}
+	id, err := p.Process(roundReceipt())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if entry, _ := p.Store.Get(id); entry.Points != 105 {
+		t.Errorf("points = %d, want 105 when the earlier receipt earned no total bonus", entry.Points)
+	}
+}
+
+func TestTotalBonusOncePerDayOffByDefault(t *testing.T) {
+	p := NewProcessor(NewMapStore())
+	second := roundReceipt()
+	second.Items = second.Items[:2]
+	second.Total = "4.50"
+	for _, receipt := range []models.Receipt{roundReceipt(), second} {
+		if _, err := p.Process(receipt); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if entry, _ := p.Store.Get(ComputeReceiptID(second)); entry.Points != 10+25+5+10 {
+		t.Errorf("points = %d, want the quarter bonus without the option", entry.Points)
+	}
+}
+
+func TestTotalBonusOncePerDayHonorsGates(t *testing.T) {
+	p := NewProcessor(NewMapStore())
+	p.TotalBonusOncePerDay = true
+	p.Rules.RuleGates = map[string]models.ReceiptPredicate{
+		"round_dollar":     MinTotal(500, ""),
+		"quarter_multiple": MinTotal(500, ""),
+	}
+
+	small := roundReceipt()
+	small.Items = small.Items[:2]
+	small.Total = "4.50" // a quarter multiple, but gated off
+	if _, err := p.Process(small); err != nil {
+		t.Fatal(err)
+	}
+	id, err := p.Process(roundReceipt())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if entry, _ := p.Store.Get(id); entry.Points != 105 {
+		t.Errorf("points = %d, want 105 when the earlier receipt's bonuses were gated off", entry.Points)
+	}
+}
@@ -0,0 +1,56 @@
+package services
+
+import (
+	"strings"
+
+	"receipt-processor/models"
+)
+
+// NormalizeDecimalSeparator returns a copy of receipt with sep replaced by
+// "." in its total, subtotal, tax and item prices and discounts. An empty or
+// "." sep returns the receipt unchanged.
+func NormalizeDecimalSeparator(receipt models.Receipt, sep string) models.Receipt {
+	if sep == "" || sep == "." {
+		return receipt
+	}
+	receipt.Total = normalizeAmount(receipt.Total, sep)
+	receipt.Subtotal = normalizeAmount(receipt.Subtotal, sep)
+	receipt.Tax = normalizeAmount(receipt.Tax, sep)
+	items := make([]models.Item, len(receipt.Items))
+	for i, item := range receipt.Items {
+		item.Price = normalizeAmount(item.Price, sep)
+		item.Discount = normalizeAmount(item.Discount, sep)
+		items[i] = item
+	}
+	receipt.Items = items
+	return receipt
+}
+
+// trimAmounts returns a copy of receipt with surrounding whitespace removed
+// from its total, subtotal, tax and item prices and discounts.
+func trimAmounts(receipt models.Receipt) models.Receipt {
+	receipt.Total = strings.TrimSpace(receipt.Total)
+	receipt.Subtotal = strings.TrimSpace(receipt.Subtotal)
+	receipt.Tax = strings.TrimSpace(receipt.Tax)
+	items := make([]models.Item, len(receipt.Items))
+	for i, item := range receipt.Items {
+		item.Price = strings.TrimSpace(item.Price)
+		item.Discount = strings.TrimSpace(item.Discount)
+		items[i] = item
+	}
+	receipt.Items = items
+	return receipt
+}
+
+// normalizeAmount replaces the decimal separator sep in amount with ".".
+func normalizeAmount(amount, sep string) string {
+	if sep == "" || sep == "." {
+		return amount
+	}
+	return strings.Replace(amount, sep, ".", 1)
+}
+
+// parseAmount is parseCents for an amount written with decimal separator sep.
+func parseAmount(amount, sep string) (int64, error) {
+	return parseCents(normalizeAmount(amount, sep))
+}
@@ -0,0 +1,69 @@
+package services
+
+import (
+	"testing"
+
+	"receipt-processor/models"
+)
+
+func commaReceipt() models.Receipt {
+	r := roundReceipt()
+	r.Total = "9,00"
+	for i := range r.Items {
+		r.Items[i].Price = "2,25"
+	}
+	return r
+}
+
+func TestCommaSeparatorScoresLikeDot(t *testing.T) {
+	cfg := models.DefaultRuleConfig()
+	cfg.DecimalSeparator = ","
+
+	comma, err := CalculatePointsWithConfig(commaReceipt(), cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dot, err := CalculatePoints(roundReceipt())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if comma != dot {
+		t.Errorf("comma points = %d, dot points = %d", comma, dot)
+	}
+
+	if _, err := CalculatePoints(commaReceipt()); err == nil {
+		t.Error("expected comma amounts to be rejected under the default separator")
+	}
+}
+
+func TestCommaSeparatorValidation(t *testing.T) {
+	cfg := models.DefaultValidationConfig()
+	if err := ValidateReceiptWithConfig(commaReceipt(), cfg); err == nil {
+		t.Error("expected comma amounts to fail validation by default")
+	}
+	cfg.DecimalSeparator = ","
+	if err := ValidateReceiptWithConfig(commaReceipt(), cfg); err != nil {
+		t.Errorf("comma mode: %v", err)
+	}
+}
+
+func TestProcessorNormalizesDecimalSeparator(t *testing.T) {
+	store := NewMapStore()
+	p := NewProcessor(store)
+	p.Validation.DecimalSeparator = ","
+
+	id, err := p.Process(commaReceipt())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id != ComputeReceiptID(roundReceipt()) {
+		t.Error("comma receipt should share the ID of its dot equivalent")
+	}
+	entry, err := store.Get(id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if entry.Receipt.Total != "9.00" || entry.Points != 105 {
+		t.Errorf("stored total %q with %d points, want 9.00 with 105", entry.Receipt.Total, entry.Points)
+	}
+}
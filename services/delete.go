@@ -0,0 +1,26 @@
+package services
+
+import "receipt-processor/models"
+
+// DeleteWhere removes every stored receipt for which pred returns true and
+// reports how many were removed. Matching IDs are collected before any are
+// deleted, so stores that forbid mutation during Range are safe.
+func DeleteWhere(store ReceiptStore, pred func(id string, r models.Receipt) bool) (deleted int, err error) {
+	var ids []string
+	err = store.Range(func(id string, entry models.StoredReceipt) bool {
+		if pred(id, entry.Receipt) {
+			ids = append(ids, id)
+		}
+		return true
+	})
+	if err != nil {
+		return 0, err
+	}
+	for _, id := range ids {
+		if err := store.Delete(id); err != nil {
+			return deleted, err
+		}
+		deleted++
+	}
+	return deleted, nil
+}
@@ -0,0 +1,45 @@
+package services
+
+import (
+	"testing"
+
+	"receipt-processor/models"
+)
+
+func TestDeleteWhere(t *testing.T) {
+	store := NewConcurrentStore(NewMapStore())
+	other := targetReceipt()
+	other.Retailer = "Walgreens"
+	second := targetReceipt()
+	second.PurchaseDate = "2022-01-02"
+
+	for _, r := range []models.Receipt{targetReceipt(), second, other, roundReceipt()} {
+		if _, err := ProcessReceipt(r, store); err != nil {
+			t.Fatalf("ProcessReceipt: %v", err)
+		}
+	}
+
+	deleted, err := DeleteWhere(store, func(id string, r models.Receipt) bool {
+		return r.Retailer == "Target"
+	})
+	if err != nil {
+		t.Fatalf("DeleteWhere: %v", err)
+	}
+	if deleted != 2 {
+		t.Errorf("deleted = %d, want 2", deleted)
+	}
+
+	var remaining []string
+	store.Range(func(id string, entry models.StoredReceipt) bool {
+		remaining = append(remaining, entry.Receipt.Retailer)
+		return true
+	})
+	if len(remaining) != 2 {
+		t.Fatalf("remaining = %v, want Walgreens and Corner Shop", remaining)
+	}
+	for _, retailer := range remaining {
+		if retailer == "Target" {
+			t.Errorf("Target receipt survived: %v", remaining)
+		}
+	}
+}
@@ -0,0 +1,37 @@
+package services
+
+import "receipt-processor/models"
+
+// ProcessAndDescribe processes a receipt like Process and describes the
+// stored result: its ID, the receipt in the normalized form it was stored in,
+// and the stored points and breakdown, so a resubmission describes the
+// entry as it was scored. A late-submission penalty appears as its own entry
+// after the others. Entries stored without a breakdown are rescored under
+// the current rules, TotalBonusOncePerDay included, as of when they were
+// stored.
+func (p *Processor) ProcessAndDescribe(receipt models.Receipt) (models.ProcessResult, error) {
+	id, err := p.Process(receipt)
+	if err != nil {
+		return models.ProcessResult{}, err
+	}
+	entry, err := p.Store.Get(id)
+	if err != nil {
+		return models.ProcessResult{}, err
+	}
+	breakdown := entry.Breakdown
+	if breakdown == nil {
+		rules, err := p.scoringRules(id, entry.Receipt)
+		if err != nil {
+			return models.ProcessResult{}, err
+		}
+		if _, breakdown, err = breakdownAt(entry.Receipt, rules, entry.StoredAt); err != nil {
+			return models.ProcessResult{}, err
+		}
+	}
+	return models.ProcessResult{
+		ID:        id,
+		Receipt:   entry.Receipt,
+		Points:    entry.Points,
+		Breakdown: breakdown,
+	}, nil
+}
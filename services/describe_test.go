@@ -0,0 +1,153 @@
+package services
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"receipt-processor/models"
+)
+
+func breakdownSum(breakdown []models.Contribution) int {
+	sum := 0
+	for _, c := range breakdown {
+		sum += c.Points
+	}
+	return sum
+}
+
+func TestProcessAndDescribe(t *testing.T) {
+	receipt := targetReceipt()
+	receipt.Retailer = " Target "
+	receipt.Items[0].Price = "06.49"
+	p := NewProcessor(NewMapStore())
+
+	result, err := p.ProcessAndDescribe(receipt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	normalized, err := ValidateAndNormalize(receipt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(result.Receipt, normalized) {
+		t.Errorf("receipt = %+v, want the normalized form %+v", result.Receipt, normalized)
+	}
+	if result.ID != ComputeReceiptID(result.Receipt) {
+		t.Errorf("ID = %q, want the ID of the returned receipt %q", result.ID, ComputeReceiptID(result.Receipt))
+	}
+	if entry, err := p.Store.Get(result.ID); err != nil || !reflect.DeepEqual(entry.Receipt, result.Receipt) {
+		t.Errorf("stored entry %+v (%v) differs from the returned receipt", entry.Receipt, err)
+	}
+	if result.Points != 28 {
+		t.Errorf("points = %d, want 28", result.Points)
+	}
+	if len(result.Breakdown) != len(builtinRules) || breakdownSum(result.Breakdown) != result.Points {
+		t.Errorf("breakdown %+v does not account for %d points", result.Breakdown, result.Points)
+	}
+}
+
+func TestProcessAndDescribeLateSubmission(t *testing.T) {
+	p := NewProcessor(NewMapStore())
+	p.Rules.MaxSubmissionDelay = 24 * time.Hour
+	p.Rules.LateSubmissionPenalty = 20
+	p.Clock = FixedClock(time.Date(2022, 2, 1, 0, 0, 0, 0, time.UTC))
+
+	result, err := p.ProcessAndDescribe(targetReceipt())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Points != 8 {
+		t.Errorf("points = %d, want 8", result.Points)
+	}
+	last := result.Breakdown[len(result.Breakdown)-1]
+	if last != (models.Contribution{Rule: "late_submission", Points: -20}) {
+		t.Errorf("last breakdown entry = %+v, want late_submission -20", last)
+	}
+	if breakdownSum(result.Breakdown) != result.Points {
+		t.Errorf("breakdown %+v does not sum to %d", result.Breakdown, result.Points)
+	}
+}
+
+func TestProcessAndDescribeInvalid(t *testing.T) {
+	receipt := targetReceipt()
+	receipt.Items = nil
+	_, err := NewProcessor(NewMapStore()).ProcessAndDescribe(receipt)
+	assertValidationCode(t, err, CodeNoItems)
+}
+
+func TestProcessAndDescribeTotalBonusOncePerDay(t *testing.T) {
+	p := NewProcessor(NewMapStore())
+	p.TotalBonusOncePerDay = true
+	if _, err := p.Process(roundReceipt()); err != nil {
+		t.Fatal(err)
+	}
+	second := roundReceipt()
+	second.Items = second.Items[:2]
+	second.Total = "4.50"
+
+	result, err := p.ProcessAndDescribe(second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Points != 25 {
+		t.Errorf("points = %d, want 25", result.Points)
+	}
+	for _, c := range result.Breakdown {
+		switch c.Rule {
+		case "round_dollar", "quarter_multiple", "late_submission":
+			if c.Points != 0 {
+				t.Errorf("breakdown entry %+v, want the total bonuses withheld and no penalty", c)
+			}
+		}
+	}
+	if breakdownSum(result.Breakdown) != result.Points {
+		t.Errorf("breakdown %+v does not sum to %d", result.Breakdown, result.Points)
+	}
+}
+
+func TestProcessAndDescribeLatePenaltyFloorsAtZero(t *testing.T) {
+	p := NewProcessor(NewMapStore())
+	p.Rules.MaxSubmissionDelay = 24 * time.Hour
+	p.Rules.LateSubmissionPenalty = 100
+	p.Clock = FixedClock(time.Date(2022, 2, 1, 0, 0, 0, 0, time.UTC))
+
+	result, err := p.ProcessAndDescribe(targetReceipt())
+	if err != nil {
+		t.Fatal(err)
+	}
+	last := result.Breakdown[len(result.Breakdown)-1]
+	if result.Points != 0 || last != (models.Contribution{Rule: "late_submission", Points: -28}) {
+		t.Errorf("points %d with last entry %+v, want 0 with late_submission -28", result.Points, last)
+	}
+}
+
+func TestProcessAndDescribeResubmissionAfterDedupWindow(t *testing.T) {
+	now := time.Date(2022, 3, 20, 15, 0, 0, 0, time.UTC)
+	p := NewProcessor(NewMapStore())
+	p.TotalBonusOncePerDay = true
+	p.DedupWindow = time.Hour
+	p.Clock = func() time.Time { return now }
+
+	if _, err := p.Process(roundReceipt()); err != nil {
+		t.Fatal(err)
+	}
+	second := roundReceipt()
+	second.Items = second.Items[:2]
+	second.Total = "4.50"
+	if _, err := p.Process(second); err != nil {
+		t.Fatal(err)
+	}
+
+	now = now.Add(2 * time.Hour)
+	result, err := p.ProcessAndDescribe(roundReceipt())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Points != 105 {
+		t.Errorf("points = %d, want the stored 105", result.Points)
+	}
+	if breakdownSum(result.Breakdown) != result.Points {
+		t.Errorf("breakdown %+v does not sum to %d", result.Breakdown, result.Points)
+	}
+}
@@ -0,0 +1,60 @@
+package services
+
+import "testing"
+
+func TestItemDiscountReducesDescriptionPoints(t *testing.T) {
+	tests := []struct {
+		discount string
+		want     int
+	}{
+		{"", 28},
+		{"0.00", 28},
+		{"5.00", 27},  // 7.25 * 0.2 rounds up to 2 instead of 3
+		{"12.25", 25}, // free item contributes nothing
+	}
+	for _, tt := range tests {
+		receipt := targetReceipt()
+		receipt.Items[1].Discount = tt.discount // Emils Cheese Pizza, 12.25
+		got, err := CalculatePoints(receipt)
+		if err != nil {
+			t.Fatalf("discount %q: %v", tt.discount, err)
+		}
+		if got != tt.want {
+			t.Errorf("discount %q: got %d points, want %d", tt.discount, got, tt.want)
+		}
+	}
+}
+
+func TestItemDiscountValidation(t *testing.T) {
+	tests := []struct {
+		discount string
+		valid    bool
+	}{
+		{"", true},
+		{"1.00", true},
+		{"12.25", true},
+		{"12.26", false},
+		{"1", false},
+		{"-1.00", false},
+	}
+	for _, tt := range tests {
+		receipt := targetReceipt()
+		receipt.Items[1].Discount = tt.discount
+		err := ValidateReceipt(receipt)
+		if tt.valid {
+			if err != nil {
+				t.Errorf("discount %q: %v", tt.discount, err)
+			}
+			continue
+		}
+		assertValidationCode(t, err, CodeInvalidFormat)
+	}
+}
+
+func TestItemDiscountChangesID(t *testing.T) {
+	discounted := targetReceipt()
+	discounted.Items[1].Discount = "1.00"
+	if ComputeReceiptID(discounted) == ComputeReceiptID(targetReceipt()) {
+		t.Error("adding a discount did not change the ID")
+	}
+}
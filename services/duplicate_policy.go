@@ -0,0 +1,23 @@
+package services
+
+// DuplicatePolicy decides whether a receipt whose ID is id duplicates one
+// already in store.
+type DuplicatePolicy interface {
+	IsDuplicate(id string, store ReceiptStore) (bool, error)
+}
+
+// DuplicatePolicyFunc adapts a function to a DuplicatePolicy.
+type DuplicatePolicyFunc func(id string, store ReceiptStore) (bool, error)
+
+func (f DuplicatePolicyFunc) IsDuplicate(id string, store ReceiptStore) (bool, error) {
+	return f(id, store)
+}
+
+// ExactHashPolicy treats a receipt as a duplicate when an entry is already
+// stored under its ID, that is, when a receipt with the same content hash
+// has been processed. It is the default policy.
+type ExactHashPolicy struct{}
+
+func (ExactHashPolicy) IsDuplicate(id string, store ReceiptStore) (bool, error) {
+	return store.Has(id)
+}
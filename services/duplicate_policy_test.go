@@ -0,0 +1,62 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestProcessorDuplicatePolicy(t *testing.T) {
+	always := DuplicatePolicyFunc(func(string, ReceiptStore) (bool, error) { return true, nil })
+	never := DuplicatePolicyFunc(func(string, ReceiptStore) (bool, error) { return false, nil })
+
+	t.Run("always duplicate", func(t *testing.T) {
+		store := NewMapStore()
+		p := NewProcessor(store)
+		p.Duplicates = always
+		if _, err := p.Process(targetReceipt()); !errors.Is(err, ErrDuplicateReceipt) {
+			t.Errorf("err = %v, want ErrDuplicateReceipt", err)
+		}
+		if n, _ := CountReceipts(store); n != 0 {
+			t.Errorf("store holds %d receipts, want 0", n)
+		}
+	})
+
+	t.Run("never duplicate", func(t *testing.T) {
+		store := NewMapStore()
+		p := NewProcessor(store)
+		p.Duplicates = never
+		for i := 0; i < 2; i++ {
+			if _, err := p.Process(targetReceipt()); err != nil {
+				t.Errorf("submission %d: %v", i+1, err)
+			}
+		}
+	})
+
+	t.Run("always duplicate with dedup window", func(t *testing.T) {
+		p := NewProcessor(NewMapStore())
+		p.Duplicates = always
+		p.DedupWindow = time.Minute
+		if _, err := p.Process(targetReceipt()); !errors.Is(err, ErrDuplicateReceipt) {
+			t.Errorf("err = %v, want ErrDuplicateReceipt", err)
+		}
+	})
+
+	t.Run("default exact hash", func(t *testing.T) {
+		p := NewProcessor(NewMapStore())
+		if _, err := p.Process(targetReceipt()); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := p.Process(targetReceipt()); !errors.Is(err, ErrDuplicateReceipt) {
+			t.Errorf("resubmission: err = %v, want ErrDuplicateReceipt", err)
+		}
+	})
+}
+
+func TestGenerateReceiptIDWithPolicyError(t *testing.T) {
+	errPolicy := errors.New("lookup failed")
+	failing := DuplicatePolicyFunc(func(string, ReceiptStore) (bool, error) { return false, errPolicy })
+	if _, err := GenerateReceiptIDWithPolicy(targetReceipt(), NewMapStore(), failing); !errors.Is(err, errPolicy) {
+		t.Errorf("err = %v, want the policy's error", err)
+	}
+}
@@ -0,0 +1,111 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"receipt-processor/models"
+)
+
+// ScoreFromMap scores a receipt decoded into a generic map, as produced by
+// json.Unmarshal into interface{}. Keys follow the receipt's JSON names.
+// Amounts may be strings ("12.50") or numbers (12.5); numbers must be whole
+// cents and are rendered with two decimals before the receipt is validated
+// and scored.
+func ScoreFromMap(m map[string]interface{}) (int, error) {
+	receipt, err := receiptFromMap(m)
+	if err != nil {
+		return 0, err
+	}
+	if err := ValidateReceipt(receipt); err != nil {
+		return 0, err
+	}
+	return CalculatePoints(receipt)
+}
+
+func receiptFromMap(m map[string]interface{}) (models.Receipt, error) {
+	var receipt models.Receipt
+	var err error
+	if receipt.Retailer, err = stringField(m, "retailer"); err != nil {
+		return models.Receipt{}, err
+	}
+	if receipt.PurchaseDate, err = stringField(m, "purchaseDate"); err != nil {
+		return models.Receipt{}, err
+	}
+	if receipt.PurchaseTime, err = stringField(m, "purchaseTime"); err != nil {
+		return models.Receipt{}, err
+	}
+	if receipt.Total, err = amountField(m, "total"); err != nil {
+		return models.Receipt{}, err
+	}
+
+	rawItems, ok := m["items"]
+	if !ok || rawItems == nil {
+		return receipt, nil
+	}
+	items, ok := rawItems.([]interface{})
+	if !ok {
+		return models.Receipt{}, fmt.Errorf("items: expected a list, got %T", rawItems)
+	}
+	for i, raw := range items {
+		im, ok := raw.(map[string]interface{})
+		if !ok {
+			return models.Receipt{}, fmt.Errorf("items[%d]: expected an object, got %T", i, raw)
+		}
+		var item models.Item
+		if item.ShortDescription, err = stringField(im, "shortDescription"); err != nil {
+			return models.Receipt{}, fmt.Errorf("items[%d]: %w", i, err)
+		}
+		if item.Price, err = amountField(im, "price"); err != nil {
+			return models.Receipt{}, fmt.Errorf("items[%d]: %w", i, err)
+		}
+		receipt.Items = append(receipt.Items, item)
+	}
+	return receipt, nil
+}
+
+// stringField returns m[key] as a string; a missing key yields "".
+func stringField(m map[string]interface{}, key string) (string, error) {
+	v, ok := m[key]
+	if !ok || v == nil {
+		return "", nil
+	}
+	s, ok := v.(string)
+	if !ok {
+		return "", fmt.Errorf("%s: expected a string, got %T", key, v)
+	}
+	return s, nil
+}
+
+// amountField returns m[key] as a decimal amount string, accepting strings
+// and JSON numbers. A number with more than two decimal places is an error
+// rather than being rounded.
+func amountField(m map[string]interface{}, key string) (string, error) {
+	var f float64
+	switch v := m[key].(type) {
+	case nil:
+		return "", nil
+	case string:
+		return v, nil
+	case float64:
+		f = v
+	case int:
+		f = float64(v)
+	case int64:
+		f = float64(v)
+	case json.Number:
+		parsed, err := v.Float64()
+		if err != nil {
+			return "", fmt.Errorf("%s: %w", key, err)
+		}
+		f = parsed
+	default:
+		return "", fmt.Errorf("%s: expected a string or number, got %T", key, v)
+	}
+	if _, frac, _ := strings.Cut(strconv.FormatFloat(f, 'f', -1, 64), "."); len(frac) > 2 {
+		return "", fmt.Errorf("%s: %v has more than two decimal places", key, f)
+	}
+	return strconv.FormatFloat(f, 'f', 2, 64), nil
+}
@@ -0,0 +1,77 @@
+package services
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestScoreFromMap(t *testing.T) {
+	// Prices mix string and numeric forms, as loosely typed clients send them.
+	doc := `{
+		"retailer": "Target",
+		"purchaseDate": "2022-01-01",
+		"purchaseTime": "13:01",
+		"items": [
+			{"shortDescription": "Mountain Dew 12PK", "price": "6.49"},
+			{"shortDescription": "Emils Cheese Pizza", "price": 12.25},
+			{"shortDescription": "Knorr Creamy Chicken", "price": "1.26"},
+			{"shortDescription": "Doritos Nacho Cheese", "price": 3.35},
+			{"shortDescription": "   Klarbrunn 12-PK 12 FL OZ  ", "price": 12}
+		],
+		"total": 35.35
+	}`
+	var m map[string]interface{}
+	if err := json.Unmarshal([]byte(doc), &m); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := ScoreFromMap(m)
+	if err != nil {
+		t.Fatalf("ScoreFromMap: %v", err)
+	}
+	if got != 28 {
+		t.Errorf("points = %d, want 28", got)
+	}
+}
+
+func TestScoreFromMapErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		m    map[string]interface{}
+	}{
+		{"retailer not a string", map[string]interface{}{"retailer": 5}},
+		{"items not a list", map[string]interface{}{"retailer": "Target", "items": "x"}},
+		{"price is a bool", map[string]interface{}{
+			"retailer": "Target",
+			"items":    []interface{}{map[string]interface{}{"shortDescription": "a", "price": true}},
+		}},
+		{"fails validation", map[string]interface{}{"retailer": "Target"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ScoreFromMap(tt.m); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
+
+func TestAmountFieldNumbers(t *testing.T) {
+	tests := []struct {
+		v       interface{}
+		want    string
+		wantErr bool
+	}{
+		{12.25, "12.25", false},
+		{12, "12.00", false},
+		{json.Number("0.1"), "0.10", false},
+		{1.005, "", true},
+		{json.Number("3.999"), "", true},
+	}
+	for _, tt := range tests {
+		got, err := amountField(map[string]interface{}{"price": tt.v}, "price")
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("amountField(%v) = %q, %v; want %q, error %v", tt.v, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package services
+
+import (
+	"fmt"
+	"sync"
+
+	"receipt-processor/models"
+)
+
+// Store event operations.
+const (
+	EventSet    = "set"
+	EventDelete = "delete"
+)
+
+// StoreEvent is one recorded store mutation. Entry is empty for deletes.
+type StoreEvent struct {
+	Op    string               `json:"op"`
+	ID    string               `json:"id"`
+	Entry models.StoredReceipt `json:"entry"`
+}
+
+// EventLog is an append-only record of store mutations, safe for
+// concurrent use.
+type EventLog struct {
+	mu     sync.Mutex
+	events []StoreEvent
+}
+
+func (l *EventLog) append(e StoreEvent) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.events = append(l.events, e)
+}
+
+// Events returns a copy of the recorded events in the order they happened.
+func (l *EventLog) Events() []StoreEvent {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]StoreEvent(nil), l.events...)
+}
+
+// EventLogStore records every successful Set and Delete on the wrapped
+// store into an EventLog.
+type EventLogStore struct {
+	inner ReceiptStore
+	log   *EventLog
+}
+
+// NewEventLogStore wraps inner so that its mutations are appended to log.
+func NewEventLogStore(inner ReceiptStore, log *EventLog) *EventLogStore {
+	return &EventLogStore{inner: inner, log: log}
+}
+
+func (s *EventLogStore) Get(id string) (models.StoredReceipt, error) {
+	return s.inner.Get(id)
+}
+
+func (s *EventLogStore) Set(id string, entry models.StoredReceipt) error {
+	if err := s.inner.Set(id, entry); err != nil {
+		return err
+	}
+	s.log.append(StoreEvent{Op: EventSet, ID: id, Entry: entry})
+	return nil
+}
+
+func (s *EventLogStore) Has(id string) (bool, error) {
+	return s.inner.Has(id)
+}
+
+func (s *EventLogStore) Delete(id string) error {
+	if err := s.inner.Delete(id); err != nil {
+		return err
+	}
+	s.log.append(StoreEvent{Op: EventDelete, ID: id})
+	return nil
+}
+
+func (s *EventLogStore) Range(fn func(id string, entry models.StoredReceipt) bool) error {
+	return s.inner.Range(fn)
+}
+
+// ReplayEventLog applies the events in log to store in order, rebuilding the
+// state of the store they were recorded from.
+func ReplayEventLog(store ReceiptStore, log *EventLog) error {
+	for i, e := range log.Events() {
+		var err error
+		switch e.Op {
+		case EventSet:
+			err = store.Set(e.ID, e.Entry)
+		case EventDelete:
+			err = store.Delete(e.ID)
+		default:
+			err = fmt.Errorf("unknown operation %q", e.Op)
+		}
+		if err != nil {
+			return fmt.Errorf("replaying event %d: %w", i, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,59 @@
+package services
+
+import (
+	"reflect"
+	"testing"
+
+	"receipt-processor/models"
+)
+
+// storeContents returns every entry in store keyed by ID.
+func storeContents(t *testing.T, store ReceiptStore) map[string]models.StoredReceipt {
+	t.Helper()
+	contents := make(map[string]models.StoredReceipt)
+	if err := store.Range(func(id string, entry models.StoredReceipt) bool {
+		contents[id] = entry
+		return true
+	}); err != nil {
+		t.Fatal(err)
+	}
+	return contents
+}
+
+func TestReplayEventLog(t *testing.T) {
+	var log EventLog
+	store := NewEventLogStore(NewMapStore(), &log)
+
+	targetID, err := ProcessReceipt(targetReceipt(), store)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ProcessReceipt(roundReceipt(), store); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ProcessReceipt(mmReceipt(), store); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Delete(targetID); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Set("manual", models.StoredReceipt{Points: 7}); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := len(log.Events()); got != 5 {
+		t.Errorf("recorded %d events, want 5", got)
+	}
+
+	replayed := NewMapStore()
+	if err := ReplayEventLog(replayed, &log); err != nil {
+		t.Fatalf("ReplayEventLog: %v", err)
+	}
+	want := storeContents(t, store)
+	if got := storeContents(t, replayed); !reflect.DeepEqual(got, want) {
+		t.Errorf("replayed store = %v, want %v", got, want)
+	}
+	if ok, _ := replayed.Has(targetID); ok {
+		t.Error("deleted entry reappeared after replay")
+	}
+}
@@ -0,0 +1,43 @@
+package services
+
+import (
+	"encoding/csv"
+	"io"
+	"sort"
+	"strconv"
+
+	"receipt-processor/models"
+)
+
+// scoresCSVHeader is the first row written by WriteScoresCSV.
+var scoresCSVHeader = []string{"id", "retailer", "purchaseDate", "total", "points"}
+
+// WriteScoresCSV writes one CSV row per stored receipt, after a header row,
+// with the receipt's ID, retailer, purchase date, total and points. Rows are
+// ordered by ID.
+func WriteScoresCSV(w io.Writer, store ReceiptStore) error {
+	var rows [][]string
+	err := store.Range(func(id string, entry models.StoredReceipt) bool {
+		rows = append(rows, []string{
+			id,
+			entry.Receipt.Retailer,
+			entry.Receipt.PurchaseDate,
+			entry.Receipt.Total,
+			strconv.Itoa(entry.Points),
+		})
+		return true
+	})
+	if err != nil {
+		return err
+	}
+	sort.Slice(rows, func(i, j int) bool { return rows[i][0] < rows[j][0] })
+
+	cw := csv.NewWriter(w)
+	if err := cw.Write(scoresCSVHeader); err != nil {
+		return err
+	}
+	if err := cw.WriteAll(rows); err != nil {
+		return err
+	}
+	return cw.Error()
+}
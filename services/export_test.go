@@ -0,0 +1,49 @@
+package services
+
+import (
+	"bytes"
+	"encoding/csv"
+	"reflect"
+	"sort"
+	"strconv"
+	"testing"
+
+	"receipt-processor/models"
+)
+
+func TestWriteScoresCSV(t *testing.T) {
+	store := NewMapStore()
+	var want [][]string
+	for _, r := range []models.Receipt{targetReceipt(), mmReceipt()} {
+		id, err := ProcessReceipt(r, store)
+		if err != nil {
+			t.Fatal(err)
+		}
+		points, _ := CalculatePoints(r)
+		want = append(want, []string{id, r.Retailer, r.PurchaseDate, r.Total, strconv.Itoa(points)})
+	}
+	// Stored directly, since validation would reject the retailer name, to
+	// check that fields needing quotes survive the round trip.
+	quoted := models.StoredReceipt{Receipt: roundReceipt(), Points: 7}
+	quoted.Receipt.Retailer = "Smith, \"Jones\" & Co"
+	if err := store.Set("quoted-id", quoted); err != nil {
+		t.Fatal(err)
+	}
+	want = append(want, []string{"quoted-id", quoted.Receipt.Retailer, "2022-03-20", "9.00", "7"})
+	sort.Slice(want, func(i, j int) bool { return want[i][0] < want[j][0] })
+
+	var buf bytes.Buffer
+	if err := WriteScoresCSV(&buf, store); err != nil {
+		t.Fatalf("WriteScoresCSV: %v", err)
+	}
+	rows, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("parse CSV: %v", err)
+	}
+	if len(rows) == 0 || !reflect.DeepEqual(rows[0], scoresCSVHeader) {
+		t.Fatalf("header = %v, want %v", rows, scoresCSVHeader)
+	}
+	if !reflect.DeepEqual(rows[1:], want) {
+		t.Errorf("rows = %v, want %v", rows[1:], want)
+	}
+}
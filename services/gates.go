@@ -0,0 +1,14 @@
+package services
+
+import "receipt-processor/models"
+
+// MinTotal returns a predicate that holds for receipts whose total is at
+// least cents, reading the total with decimal separator sep as in
+// RuleConfig.DecimalSeparator. Receipts with an unparseable total fail it.
+// Use it in RuleConfig.RuleGates to reserve a bonus for larger baskets.
+func MinTotal(cents int64, sep string) models.ReceiptPredicate {
+	return func(receipt models.Receipt) bool {
+		total, err := parseAmount(receipt.Total, sep)
+		return err == nil && total >= cents
+	}
+}
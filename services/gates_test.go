@@ -0,0 +1,58 @@
+package services
+
+import (
+	"testing"
+
+	"receipt-processor/models"
+)
+
+func TestRuleGateMinTotal(t *testing.T) {
+	cfg := models.DefaultRuleConfig()
+	cfg.RuleGates = map[string]models.ReceiptPredicate{"time_window": MinTotal(2000, cfg.DecimalSeparator)}
+
+	tests := []struct {
+		total string
+		want  int
+	}{
+		{"19.99", 0},
+		{"20.00", 10},
+		{"45.10", 10},
+	}
+	for _, tt := range tests {
+		receipt := roundReceipt() // purchased at 14:33, inside the default window
+		receipt.Total = tt.total
+
+		got, breakdown, err := CalculatePointsWithBreakdown(receipt, cfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, c := range breakdown {
+			if c.Rule == "time_window" && c.Points != tt.want {
+				t.Errorf("total %s: time_window = %d, want %d", tt.total, c.Points, tt.want)
+			}
+		}
+		ungated, _ := CalculatePoints(receipt)
+		if diff := ungated - got; diff != 10-tt.want {
+			t.Errorf("total %s: gate changed the score by %d, want %d", tt.total, diff, 10-tt.want)
+		}
+	}
+}
+
+func TestMinTotalCommaSeparator(t *testing.T) {
+	receipt := targetReceipt()
+	receipt.Total = "6,49"
+	if !MinTotal(100, ",")(receipt) {
+		t.Error("MinTotal(100, \",\") rejected a total of 6,49")
+	}
+	if MinTotal(700, ",")(receipt) {
+		t.Error("MinTotal(700, \",\") accepted a total of 6,49")
+	}
+}
+
+func TestMinTotalRejectsUnparseableTotal(t *testing.T) {
+	receipt := targetReceipt()
+	receipt.Total = "n/a"
+	if MinTotal(0, "")(receipt) {
+		t.Error("MinTotal accepted an unparseable total")
+	}
+}
@@ -0,0 +1,231 @@
+package services
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"receipt-processor/models"
+)
+
+// ErrDuplicateReceipt is returned when a receipt with the same content has
+// already been stored.
+var ErrDuplicateReceipt = errors.New("receipt already processed")
+
+// ID hashing schemes. Each scheme defines the byte sequence fed to sha256.
+const (
+	// IDSchemeV1 hashes the fields pipe-joined in submission order.
+	IDSchemeV1 = "v1"
+	// IDSchemeV2 hashes a canonical form: trimmed fields, one per line, with
+	// items sorted so that item order does not change the ID. Item SKUs and
+	// discounts are included when present.
+	IDSchemeV2 = "v2"
+
+	// CurrentIDScheme is the scheme used by ComputeReceiptID.
+	CurrentIDScheme = IDSchemeV2
+)
+
+var idSchemes = map[string]func(w *bufio.Writer, receipt models.Receipt){
+	IDSchemeV1: writeV1,
+	IDSchemeV2: writeV2,
+}
+
+// ComputeReceiptID returns the deterministic ID of a receipt under the
+// current scheme: the hex sha256 of the scoring-relevant content of its
+// normalized form (see ValidateAndNormalize), so it is the ID Process
+// stores the receipt under. Provenance fields such as ImageURL are not part
+// of the hash.
+func ComputeReceiptID(receipt models.Receipt) string {
+	id, _ := ComputeReceiptIDWithScheme(receipt, CurrentIDScheme)
+	return id
+}
+
+// ComputeReceiptIDWithScheme returns the receipt's ID under a specific
+// hashing scheme.
+func ComputeReceiptIDWithScheme(receipt models.Receipt, scheme string) (string, error) {
+	sum, err := receiptHash(receipt, scheme)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(sum), nil
+}
+
+// ComputeReceiptIDs returns the receipt's hex ID under every known scheme,
+// keyed by scheme, for lookups that span a hashing migration.
+func ComputeReceiptIDs(receipt models.Receipt) (map[string]string, error) {
+	ids := make(map[string]string, len(idSchemes))
+	for scheme := range idSchemes {
+		id, err := ComputeReceiptIDWithScheme(receipt, scheme)
+		if err != nil {
+			return nil, err
+		}
+		ids[scheme] = id
+	}
+	return ids, nil
+}
+
+// receiptHash returns the sha256 of the normalized receipt's content under
+// scheme. The content is streamed into the hash through a fixed-size
+// buffer, so memory use does not grow with the field lengths.
+func receiptHash(receipt models.Receipt, scheme string) ([]byte, error) {
+	write, ok := idSchemes[scheme]
+	if !ok {
+		return nil, fmt.Errorf("unknown ID scheme %q", scheme)
+	}
+	h := sha256.New()
+	w := bufio.NewWriterSize(h, hashBufferSize)
+	write(w, normalizeReceipt(receipt))
+	if err := w.Flush(); err != nil {
+		return nil, err
+	}
+	return h.Sum(nil), nil
+}
+
+// CanonicalString returns the exact content hashed into the receipt's ID
+// under the current scheme, after normalization. Diffing the canonical
+// strings of two receipts shows why their IDs differ.
+func CanonicalString(receipt models.Receipt) (string, error) {
+	var b strings.Builder
+	w := bufio.NewWriter(&b)
+	idSchemes[CurrentIDScheme](w, normalizeReceipt(receipt))
+	if err := w.Flush(); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// hashBufferSize is the buffer between a scheme's writes and the hash.
+const hashBufferSize = 512
+
+func writeV1(w *bufio.Writer, receipt models.Receipt) {
+	w.WriteString(strings.TrimSpace(receipt.Retailer))
+	for _, field := range []string{receipt.PurchaseDate, receipt.PurchaseTime, receipt.Total} {
+		w.WriteByte('|')
+		w.WriteString(field)
+	}
+	for _, item := range receipt.Items {
+		w.WriteByte('|')
+		w.WriteString(strings.TrimSpace(item.ShortDescription))
+		w.WriteByte('|')
+		w.WriteString(item.Price)
+	}
+}
+
+func writeV2(w *bufio.Writer, receipt models.Receipt) {
+	for _, field := range []string{IDSchemeV2, strings.TrimSpace(receipt.Retailer),
+		receipt.PurchaseDate, receipt.PurchaseTime, receipt.Total} {
+		w.WriteString(field)
+		w.WriteByte('\n')
+	}
+	items := make([]models.Item, len(receipt.Items))
+	for i, item := range receipt.Items {
+		items[i] = item
+		items[i].ShortDescription = strings.TrimSpace(item.ShortDescription)
+	}
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].ShortDescription != items[j].ShortDescription {
+			return items[i].ShortDescription < items[j].ShortDescription
+		}
+		if items[i].Price != items[j].Price {
+			return items[i].Price < items[j].Price
+		}
+		if items[i].SKU != items[j].SKU {
+			return items[i].SKU < items[j].SKU
+		}
+		if items[i].IsTax != items[j].IsTax {
+			return !items[i].IsTax
+		}
+		return items[i].Discount < items[j].Discount
+	})
+	for _, item := range items {
+		w.WriteString(item.ShortDescription)
+		w.WriteByte('\t')
+		w.WriteString(item.Price)
+		if item.IsTax {
+			w.WriteString("\ttax")
+		}
+		if item.SKU != "" {
+			w.WriteString("\tsku:")
+			w.WriteString(item.SKU)
+		}
+		if item.Discount != "" {
+			w.WriteString("\tdiscount:")
+			w.WriteString(item.Discount)
+		}
+		w.WriteByte('\n')
+	}
+}
+
+// GenerateReceiptID computes the receipt's ID and checks it against the
+// store, returning ErrDuplicateReceipt along with the ID if it is taken.
+func GenerateReceiptID(receipt models.Receipt, store ReceiptStore) (string, error) {
+	return GenerateReceiptIDWithPolicy(receipt, store, ExactHashPolicy{})
+}
+
+// GenerateReceiptIDWithPolicy is GenerateReceiptID with policy deciding
+// whether the receipt is a duplicate.
+func GenerateReceiptIDWithPolicy(receipt models.Receipt, store ReceiptStore, policy DuplicatePolicy) (string, error) {
+	return generateReceiptID(receipt, store, IDFormatHex, policy)
+}
+
+func generateReceiptID(receipt models.Receipt, store ReceiptStore, format IDFormat, policy DuplicatePolicy) (string, error) {
+	id, err := ComputeReceiptIDWithFormat(receipt, format)
+	if err != nil {
+		return "", err
+	}
+	if policy == nil {
+		policy = ExactHashPolicy{}
+	}
+	dup, err := policy.IsDuplicate(id, store)
+	if err != nil {
+		return "", err
+	}
+	if dup {
+		return id, ErrDuplicateReceipt
+	}
+	return id, nil
+}
+
+// LookupID returns the ID p would give the receipt and whether p.Store
+// already holds an entry under it. The receipt is brought into the form
+// Process hashes and the ID is computed in p.IDFormat. Unlike Process it
+// treats an existing entry as a normal result rather than an error. The
+// store is not modified.
+func (p *Processor) LookupID(receipt models.Receipt) (id string, exists bool, err error) {
+	if p.Validation.TrimTotals {
+		receipt = trimAmounts(receipt)
+	}
+	receipt = NormalizeDecimalSeparator(receipt, p.Validation.DecimalSeparator)
+	id, err = ComputeReceiptIDWithFormat(normalizeReceipt(receipt), p.IDFormat)
+	if err != nil {
+		return "", false, err
+	}
+	exists, err = p.Store.Has(id)
+	if err != nil {
+		return "", false, err
+	}
+	return id, exists, nil
+}
+
+// LookupID looks up the receipt's ID in store with the default Processor
+// for store. See Processor.LookupID.
+func LookupID(receipt models.Receipt, store ReceiptStore) (id string, exists bool, err error) {
+	return NewProcessor(store).LookupID(receipt)
+}
+
+// idPattern matches IDs accepted by ValidateID.
+var idPattern = regexp.MustCompile(`^\S+$`)
+
+// ValidateID checks that id is non-empty and free of whitespace before it is
+// used as a store key. It accepts any ID format; see ValidateIDStrict.
+func ValidateID(id string) error {
+	if !idPattern.MatchString(id) {
+		return invalid(CodeInvalidID, "ID must be non-empty and contain no whitespace")
+	}
+	return nil
+}
@@ -0,0 +1,121 @@
+package services
+
+import (
+	"encoding/base32"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"regexp"
+
+	"receipt-processor/models"
+)
+
+// IDFormat selects how the sha256 of a receipt is rendered as an ID. Every
+// format is deterministic: the same receipt always gets the same ID.
+type IDFormat string
+
+const (
+	// IDFormatHex is the full hash as 64 lowercase hex characters. It is the
+	// default, used when the format is empty.
+	IDFormatHex IDFormat = "hex"
+	// IDFormatBase32 is the full hash as 52 lowercase RFC 4648 base32
+	// characters without padding.
+	IDFormatBase32 IDFormat = "base32"
+	// IDFormatBase58 is the full hash in the Bitcoin base58 alphabet, at most
+	// 44 characters and free of look-alike characters.
+	IDFormatBase58 IDFormat = "base58"
+	// IDFormatUUID is a UUID built from the first 16 bytes of the hash with
+	// the version set to 5 and the RFC 4122 variant, in the usual 8-4-4-4-12
+	// form. It keeps 122 bits of the hash.
+	IDFormatUUID IDFormat = "uuid"
+)
+
+const base58Alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+
+var base32NoPad = base32.NewEncoding("abcdefghijklmnopqrstuvwxyz234567").WithPadding(base32.NoPadding)
+
+// idFormatPatterns match exactly the IDs each format can produce.
+var idFormatPatterns = map[IDFormat]*regexp.Regexp{
+	IDFormatHex:    regexp.MustCompile(`^[0-9a-f]{64}$`),
+	IDFormatBase32: regexp.MustCompile(`^[a-z2-7]{52}$`),
+	IDFormatBase58: regexp.MustCompile(`^[1-9A-HJ-NP-Za-km-z]{1,44}$`),
+	IDFormatUUID:   regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-5[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`),
+}
+
+// ValidateIDStrict checks that id has the shape of an ID rendered in
+// format, which an empty format treats as hex. Unlike the permissive
+// ValidateID, it rejects arbitrary tokens before they reach the store.
+func ValidateIDStrict(id string, format IDFormat) error {
+	if format == "" {
+		format = IDFormatHex
+	}
+	pattern, ok := idFormatPatterns[format]
+	if !ok {
+		return fmt.Errorf("unknown ID format %q", format)
+	}
+	if !pattern.MatchString(id) {
+		return invalid(CodeInvalidID, fmt.Sprintf("ID is not a valid %s receipt ID", format))
+	}
+	return nil
+}
+
+// ValidateID checks an ID supplied by a client before it is used as a store
+// key: with ValidateIDStrict in p.IDFormat if p.StrictIDs is set, otherwise
+// with the permissive ValidateID.
+func (p *Processor) ValidateID(id string) error {
+	if p.StrictIDs {
+		return ValidateIDStrict(id, p.IDFormat)
+	}
+	return ValidateID(id)
+}
+
+// ComputeReceiptIDWithFormat returns the receipt's ID under the current
+// scheme rendered in format.
+func ComputeReceiptIDWithFormat(receipt models.Receipt, format IDFormat) (string, error) {
+	sum, err := receiptHash(receipt, CurrentIDScheme)
+	if err != nil {
+		return "", err
+	}
+	return formatID(sum, format)
+}
+
+// formatID renders a hash in format.
+func formatID(sum []byte, format IDFormat) (string, error) {
+	switch format {
+	case "", IDFormatHex:
+		return hex.EncodeToString(sum), nil
+	case IDFormatBase32:
+		return base32NoPad.EncodeToString(sum), nil
+	case IDFormatBase58:
+		return encodeBase58(sum), nil
+	case IDFormatUUID:
+		var u [16]byte
+		copy(u[:], sum)
+		u[6] = u[6]&0x0f | 0x50
+		u[8] = u[8]&0x3f | 0x80
+		return fmt.Sprintf("%x-%x-%x-%x-%x", u[0:4], u[4:6], u[6:8], u[8:10], u[10:16]), nil
+	default:
+		return "", fmt.Errorf("unknown ID format %q", format)
+	}
+}
+
+func encodeBase58(b []byte) string {
+	n := new(big.Int).SetBytes(b)
+	radix := big.NewInt(int64(len(base58Alphabet)))
+	mod := new(big.Int)
+	var out []byte
+	for n.Sign() > 0 {
+		n.DivMod(n, radix, mod)
+		out = append(out, base58Alphabet[mod.Int64()])
+	}
+	for _, c := range b {
+		if c != 0 {
+			break
+		}
+		out = append(out, base58Alphabet[0])
+	}
+	for i, j := 0, len(out)-1; i < j; i, j = i+1, j-1 {
+		out[i], out[j] = out[j], out[i]
+	}
+	return string(out)
+}
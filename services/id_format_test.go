@@ -0,0 +1,113 @@
+package services
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestComputeReceiptIDWithFormat(t *testing.T) {
+	tests := []struct {
+		format  IDFormat
+		pattern string
+	}{
+		{IDFormatHex, `^[0-9a-f]{64}$`},
+		{IDFormatBase32, `^[a-z2-7]{52}$`},
+		{IDFormatBase58, `^[1-9A-HJ-NP-Za-km-z]{43,44}$`},
+		{IDFormatUUID, `^[0-9a-f]{8}-[0-9a-f]{4}-5[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.format), func(t *testing.T) {
+			id, err := ComputeReceiptIDWithFormat(targetReceipt(), tt.format)
+			if err != nil {
+				t.Fatalf("ComputeReceiptIDWithFormat: %v", err)
+			}
+			if !regexp.MustCompile(tt.pattern).MatchString(id) {
+				t.Errorf("id %q does not match %s", id, tt.pattern)
+			}
+			if err := ValidateID(id); err != nil {
+				t.Errorf("ValidateID(%q): %v", id, err)
+			}
+			if err := ValidateIDStrict(id, tt.format); err != nil {
+				t.Errorf("ValidateIDStrict(%q): %v", id, err)
+			}
+			again, _ := ComputeReceiptIDWithFormat(targetReceipt(), tt.format)
+			if again != id {
+				t.Errorf("not deterministic: %q then %q", id, again)
+			}
+		})
+	}
+}
+
+func TestHexFormatMatchesComputeReceiptID(t *testing.T) {
+	id, err := ComputeReceiptIDWithFormat(targetReceipt(), IDFormatHex)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id != ComputeReceiptID(targetReceipt()) {
+		t.Errorf("hex format = %q, ComputeReceiptID = %q", id, ComputeReceiptID(targetReceipt()))
+	}
+}
+
+func TestEncodeBase58(t *testing.T) {
+	tests := []struct {
+		in   []byte
+		want string
+	}{
+		{[]byte{}, ""},
+		{[]byte{0}, "1"},
+		{[]byte{0, 0, 1}, "112"},
+		{[]byte("hello world"), "StV1DL6CwTryKyV"},
+	}
+	for _, tt := range tests {
+		if got := encodeBase58(tt.in); got != tt.want {
+			t.Errorf("encodeBase58(%x) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestProcessorIDFormat(t *testing.T) {
+	p := NewProcessor(NewMapStore())
+	p.IDFormat = IDFormatUUID
+	id, err := p.Process(targetReceipt())
+	if err != nil {
+		t.Fatalf("Process: %v", err)
+	}
+	want, _ := ComputeReceiptIDWithFormat(targetReceipt(), IDFormatUUID)
+	if id != want {
+		t.Errorf("id = %q, want %q", id, want)
+	}
+	if ok, _ := p.Store.Has(id); !ok {
+		t.Error("receipt not stored under its UUID")
+	}
+}
+
+func TestValidateIDStrict(t *testing.T) {
+	hexID := ComputeReceiptID(targetReceipt())
+	uuidID, _ := ComputeReceiptIDWithFormat(targetReceipt(), IDFormatUUID)
+
+	tests := []struct {
+		name   string
+		id     string
+		format IDFormat
+		code   string
+	}{
+		{"sha256 hex", hexID, IDFormatHex, ""},
+		{"default format is hex", hexID, "", ""},
+		{"short token", "abc123", IDFormatHex, CodeInvalidID},
+		{"uppercase hex", strings.ToUpper(hexID), IDFormatHex, CodeInvalidID},
+		{"uuid under hex", uuidID, IDFormatHex, CodeInvalidID},
+		{"uuid", uuidID, IDFormatUUID, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertValidationCode(t, ValidateIDStrict(tt.id, tt.format), tt.code)
+		})
+	}
+
+	// The permissive mode still accepts arbitrary tokens.
+	if err := ValidateID("abc123"); err != nil {
+		t.Errorf("ValidateID(abc123): %v", err)
+	}
+}
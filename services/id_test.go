@@ -0,0 +1,245 @@
+package services
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+
+	"receipt-processor/models"
+)
+
+func TestComputeReceiptIDIgnoresItemOrder(t *testing.T) {
+	reordered := targetReceipt()
+	reordered.Items[0], reordered.Items[4] = reordered.Items[4], reordered.Items[0]
+
+	if ComputeReceiptID(targetReceipt()) != ComputeReceiptID(reordered) {
+		t.Error("item order changed the v2 ID")
+	}
+	v1a, _ := ComputeReceiptIDWithScheme(targetReceipt(), IDSchemeV1)
+	v1b, _ := ComputeReceiptIDWithScheme(reordered, IDSchemeV1)
+	if v1a == v1b {
+		t.Error("v1 IDs should depend on item order")
+	}
+}
+
+func TestComputeReceiptIDWithUnknownScheme(t *testing.T) {
+	if _, err := ComputeReceiptIDWithScheme(targetReceipt(), "v0"); err == nil {
+		t.Error("expected error for unknown scheme")
+	}
+}
+
+func TestComputeReceiptIDs(t *testing.T) {
+	ids, err := ComputeReceiptIDs(targetReceipt())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ids) != 2 {
+		t.Errorf("ids = %v, want one per scheme", ids)
+	}
+	for _, scheme := range []string{IDSchemeV1, IDSchemeV2} {
+		want, _ := ComputeReceiptIDWithScheme(targetReceipt(), scheme)
+		if ids[scheme] != want {
+			t.Errorf("ids[%s] = %q, want %q", scheme, ids[scheme], want)
+		}
+	}
+	if ids[CurrentIDScheme] != ComputeReceiptID(targetReceipt()) {
+		t.Error("current scheme's entry differs from ComputeReceiptID")
+	}
+	if ids[IDSchemeV1] == ids[IDSchemeV2] {
+		t.Error("schemes produced the same ID")
+	}
+}
+
+func TestCanonicalString(t *testing.T) {
+	receipt := roundReceipt()
+	receipt.Retailer = " Corner Shop "
+	receipt.Items = []models.Item{
+		{ShortDescription: "Gatorade", Price: "2.25", SKU: "G1"},
+		{ShortDescription: " Chips", Price: "1.50"},
+		{ShortDescription: "Sales Tax", Price: "0.50", IsTax: true},
+	}
+	got, err := CanonicalString(receipt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "v2\nCorner Shop\n2022-03-20\n14:33\n9.00\n" +
+		"Chips\t1.50\n" +
+		"Gatorade\t2.25\tsku:G1\n" +
+		"Sales Tax\t0.50\ttax\n"
+	if got != want {
+		t.Errorf("CanonicalString = %q, want %q", got, want)
+	}
+	if sum := sha256.Sum256([]byte(got)); hex.EncodeToString(sum[:]) != ComputeReceiptID(receipt) {
+		t.Error("the canonical string does not hash to the receipt ID")
+	}
+
+	reordered := receipt
+	reordered.Retailer = "Corner Shop"
+	reordered.Items = []models.Item{receipt.Items[2], receipt.Items[0], receipt.Items[1]}
+	if other, _ := CanonicalString(reordered); other != got {
+		t.Errorf("logically equal receipts differ:\n%q\n%q", got, other)
+	}
+
+	padded := receipt
+	padded.Total = "09.00"
+	padded.Items = []models.Item{receipt.Items[0], {ShortDescription: "Chips", Price: "01.50"}, receipt.Items[2]}
+	if other, _ := CanonicalString(padded); other != got {
+		t.Errorf("amounts are not normalized:\n%q\n%q", got, other)
+	}
+}
+
+func TestComputeReceiptIDIgnoresTaxItemOrder(t *testing.T) {
+	receipt := roundReceipt()
+	receipt.Items = []models.Item{
+		{ShortDescription: "Fee", Price: "0.50", IsTax: true},
+		{ShortDescription: "Fee", Price: "0.50"},
+	}
+	swapped := receipt
+	swapped.Items = []models.Item{receipt.Items[1], receipt.Items[0]}
+	if ComputeReceiptID(receipt) != ComputeReceiptID(swapped) {
+		t.Error("swapping items that differ only in IsTax changed the ID")
+	}
+}
+
+func TestLookupID(t *testing.T) {
+	store := NewMapStore()
+	stored, err := ProcessReceipt(targetReceipt(), store)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	id, exists, err := LookupID(targetReceipt(), store)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id != stored || !exists {
+		t.Errorf("existing receipt: LookupID = %q, %v; want %q, true", id, exists, stored)
+	}
+
+	id, exists, err = LookupID(roundReceipt(), store)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id != ComputeReceiptID(roundReceipt()) || exists {
+		t.Errorf("novel receipt: LookupID = %q, %v; want its ID, false", id, exists)
+	}
+	if ok, _ := store.Has(id); ok {
+		t.Error("LookupID stored the novel receipt")
+	}
+}
+
+func TestProcessorLookupIDUsesIDFormat(t *testing.T) {
+	p := NewProcessor(NewMapStore())
+	p.IDFormat = IDFormatUUID
+	stored, err := p.Process(targetReceipt())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	id, exists, err := p.LookupID(targetReceipt())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id != stored || !exists {
+		t.Errorf("LookupID = %q, %v; want %q, true", id, exists, stored)
+	}
+}
+
+func TestValidateID(t *testing.T) {
+	tests := []struct {
+		id   string
+		code string
+	}{
+		{ComputeReceiptID(targetReceipt()), ""},
+		{"abc-123", ""},
+		{"", CodeInvalidID},
+		{"has space", CodeInvalidID},
+		{"tab\there", CodeInvalidID},
+	}
+	for _, tt := range tests {
+		assertValidationCode(t, ValidateID(tt.id), tt.code)
+	}
+}
+
+// bufferedReceiptID is a reference implementation of each ID scheme that
+// builds the whole canonical form in memory before hashing it.
+func bufferedReceiptID(receipt models.Receipt, scheme string) string {
+	var b strings.Builder
+	switch scheme {
+	case IDSchemeV1:
+		fmt.Fprintf(&b, "%s|%s|%s|%s", strings.TrimSpace(receipt.Retailer),
+			receipt.PurchaseDate, receipt.PurchaseTime, receipt.Total)
+		for _, item := range receipt.Items {
+			fmt.Fprintf(&b, "|%s|%s", strings.TrimSpace(item.ShortDescription), item.Price)
+		}
+	case IDSchemeV2:
+		fmt.Fprintf(&b, "v2\n%s\n%s\n%s\n%s\n", strings.TrimSpace(receipt.Retailer),
+			receipt.PurchaseDate, receipt.PurchaseTime, receipt.Total)
+		lines := make([]string, len(receipt.Items))
+		for i, item := range receipt.Items {
+			lines[i] = strings.TrimSpace(item.ShortDescription) + "\t" + item.Price
+			if item.IsTax {
+				lines[i] += "\ttax"
+			}
+			if item.SKU != "" {
+				lines[i] += "\tsku:" + item.SKU
+			}
+			if item.Discount != "" {
+				lines[i] += "\tdiscount:" + item.Discount
+			}
+		}
+		sort.Strings(lines)
+		for _, line := range lines {
+			b.WriteString(line + "\n")
+		}
+	}
+	sum := sha256.Sum256([]byte(b.String()))
+	return hex.EncodeToString(sum[:])
+}
+
+func manyItemReceipt(n int) models.Receipt {
+	receipt := targetReceipt()
+	receipt.Items = make([]models.Item, n)
+	for i := range receipt.Items {
+		receipt.Items[i] = models.Item{
+			ShortDescription: fmt.Sprintf(" Item %04d ", n-i),
+			Price:            fmt.Sprintf("%d.%02d", i%50, i%100),
+			IsTax:            i%97 == 0,
+		}
+	}
+	return receipt
+}
+
+func TestStreamingIDMatchesBuffered(t *testing.T) {
+	receipts := []models.Receipt{targetReceipt(), roundReceipt(), mmReceipt(), taxReceipt(), manyItemReceipt(1000)}
+	for _, scheme := range []string{IDSchemeV1, IDSchemeV2} {
+		for i, receipt := range receipts {
+			got, err := ComputeReceiptIDWithScheme(receipt, scheme)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if want := bufferedReceiptID(receipt, scheme); got != want {
+				t.Errorf("%s receipt %d: streaming ID %s, buffered %s", scheme, i, got, want)
+			}
+		}
+	}
+}
+
+func BenchmarkComputeReceiptID1000Items(b *testing.B) {
+	receipt := manyItemReceipt(1000)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		ComputeReceiptID(receipt)
+	}
+}
+
+func BenchmarkBufferedReceiptID1000Items(b *testing.B) {
+	receipt := manyItemReceipt(1000)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		bufferedReceiptID(receipt, IDSchemeV2)
+	}
+}
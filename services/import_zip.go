@@ -0,0 +1,75 @@
+package services
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+
+	"receipt-processor/models"
+)
+
+// maxImportFile bounds the size of a single receipt file in an import.
+const maxImportFile = 1 << 20
+
+// ImportZip processes every .json file in the ZIP archive read from r as a
+// receipt submission to p, like WarmStore does for NDJSON. Other entries
+// are skipped. Files that cannot be read, decoded, validated or stored are
+// listed in the report's Failed; the rest are still imported. The error is
+// non-nil only when r is not a readable ZIP archive.
+func (p *Processor) ImportZip(r io.ReaderAt, size int64) (models.ImportReport, error) {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return models.ImportReport{}, fmt.Errorf("open zip archive: %w", err)
+	}
+	var report models.ImportReport
+	for _, f := range zr.File {
+		if f.FileInfo().IsDir() || !strings.EqualFold(path.Ext(f.Name), ".json") {
+			report.Skipped++
+			continue
+		}
+		receipt, err := readZipReceipt(f)
+		if err == nil {
+			_, err = p.Process(receipt)
+		}
+		switch {
+		case errors.Is(err, ErrDuplicateReceipt):
+			report.Duplicates++
+		case err != nil:
+			report.Failed = append(report.Failed, models.ImportFailure{File: f.Name, Error: err.Error()})
+		default:
+			report.Imported++
+		}
+	}
+	return report, nil
+}
+
+// ImportZip imports a ZIP archive of receipts into store with the default
+// Processor for store. See Processor.ImportZip.
+func ImportZip(r io.ReaderAt, size int64, store ReceiptStore) (models.ImportReport, error) {
+	return NewProcessor(store).ImportZip(r, size)
+}
+
+// readZipReceipt decodes the receipt held in a ZIP entry.
+func readZipReceipt(f *zip.File) (models.Receipt, error) {
+	rc, err := f.Open()
+	if err != nil {
+		return models.Receipt{}, err
+	}
+	defer rc.Close()
+	data, err := io.ReadAll(io.LimitReader(rc, maxImportFile+1))
+	if err != nil {
+		return models.Receipt{}, err
+	}
+	if len(data) > maxImportFile {
+		return models.Receipt{}, fmt.Errorf("file exceeds %d bytes", maxImportFile)
+	}
+	var receipt models.Receipt
+	if err := json.Unmarshal(data, &receipt); err != nil {
+		return models.Receipt{}, err
+	}
+	return receipt, nil
+}
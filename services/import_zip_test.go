@@ -0,0 +1,105 @@
+package services
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"testing"
+)
+
+func buildZip(t *testing.T, files map[string][]byte) *bytes.Reader {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for name, data := range files {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w.Write(data)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return bytes.NewReader(buf.Bytes())
+}
+
+func mustJSON(t *testing.T, v interface{}) []byte {
+	t.Helper()
+	b, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+func TestImportZip(t *testing.T) {
+	noItems := targetReceipt()
+	noItems.Items = nil
+	archive := buildZip(t, map[string][]byte{
+		"receipts/target.json":      mustJSON(t, targetReceipt()),
+		"receipts/corner.JSON":      mustJSON(t, roundReceipt()),
+		"receipts/target-copy.json": mustJSON(t, targetReceipt()),
+		"receipts/no-items.json":    mustJSON(t, noItems),
+		"receipts/broken.json":      []byte(`{"retailer": `),
+		"receipts/README.txt":       []byte("scanned by the front desk"),
+	})
+
+	store := NewMapStore()
+	report, err := ImportZip(archive, archive.Size(), store)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Imported != 2 || report.Duplicates != 1 || report.Skipped != 1 || len(report.Failed) != 2 {
+		t.Errorf("report = %+v, want 2 imported, 1 duplicate, 1 skipped, 2 failed", report)
+	}
+	failed := map[string]bool{}
+	for _, f := range report.Failed {
+		failed[f.File] = true
+	}
+	if !failed["receipts/no-items.json"] || !failed["receipts/broken.json"] {
+		t.Errorf("Failed = %+v, want no-items.json and broken.json", report.Failed)
+	}
+
+	entry, err := store.Get(ComputeReceiptID(roundReceipt()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if entry.Points != 105 {
+		t.Errorf("imported points = %d, want 105", entry.Points)
+	}
+}
+
+func TestImportZipNotAnArchive(t *testing.T) {
+	r := bytes.NewReader([]byte("not a zip"))
+	if _, err := ImportZip(r, r.Size(), NewMapStore()); err == nil {
+		t.Error("expected an error for a non-ZIP input")
+	}
+}
+
+func TestProcessorImportZipUsesProcessorSettings(t *testing.T) {
+	p := NewProcessor(NewMapStore())
+	p.IDFormat = IDFormatBase32
+	p.Rules.OddDayPoints = 20
+
+	archive := buildZip(t, map[string][]byte{"target.json": mustJSON(t, targetReceipt())})
+	report, err := p.ImportZip(archive, archive.Size())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Imported != 1 {
+		t.Fatalf("report = %+v, want 1 imported", report)
+	}
+
+	id, err := ComputeReceiptIDWithFormat(targetReceipt(), IDFormatBase32)
+	if err != nil {
+		t.Fatal(err)
+	}
+	entry, err := p.Store.Get(id)
+	if err != nil {
+		t.Fatalf("Get(%s): %v", id, err)
+	}
+	if entry.Points != 42 {
+		t.Errorf("points = %d, want 42", entry.Points)
+	}
+}
@@ -0,0 +1,67 @@
+package services
+
+import (
+	"fmt"
+	"time"
+
+	"receipt-processor/models"
+)
+
+// CalculatePointsAt scores a receipt submitted at submittedAt, applying the
+// late-submission penalty when the gap since purchase exceeds
+// cfg.MaxSubmissionDelay. Purchase date and time are interpreted as UTC.
+func CalculatePointsAt(receipt models.Receipt, cfg models.RuleConfig, submittedAt time.Time) (int, error) {
+	points, err := CalculatePointsWithConfig(receipt, cfg)
+	if err != nil {
+		return 0, err
+	}
+	penalty, err := latePenalty(receipt, points, cfg, submittedAt)
+	if err != nil {
+		return 0, err
+	}
+	return points - penalty, nil
+}
+
+// breakdownAt is CalculatePointsWithBreakdown for a receipt submitted at
+// submittedAt. A late-submission penalty is applied as in CalculatePointsAt
+// and appears as its own entry after the others.
+func breakdownAt(receipt models.Receipt, cfg models.RuleConfig, submittedAt time.Time) (int, []models.Contribution, error) {
+	total, breakdown, err := CalculatePointsWithBreakdown(receipt, cfg)
+	if err != nil {
+		return 0, nil, err
+	}
+	penalty, err := latePenalty(receipt, total, cfg, submittedAt)
+	if err != nil {
+		return 0, nil, err
+	}
+	if penalty != 0 {
+		breakdown = append(breakdown, models.Contribution{Rule: lateRule, Points: -penalty})
+	}
+	return total - penalty, breakdown, nil
+}
+
+// latePenalty returns how many of points the late-submission penalty takes
+// from a receipt submitted at submittedAt: cfg.LateSubmissionPenalty, or
+// less where that would go below zero.
+func latePenalty(receipt models.Receipt, points int, cfg models.RuleConfig, submittedAt time.Time) (int, error) {
+	if cfg.MaxSubmissionDelay <= 0 || cfg.LateSubmissionPenalty == 0 {
+		return 0, nil
+	}
+	purchased, err := purchaseMoment(receipt)
+	if err != nil {
+		return 0, err
+	}
+	if submittedAt.Sub(purchased) <= cfg.MaxSubmissionDelay {
+		return 0, nil
+	}
+	return points - max(points-cfg.LateSubmissionPenalty, 0), nil
+}
+
+// purchaseMoment combines the purchase date and time into a UTC instant.
+func purchaseMoment(receipt models.Receipt) (time.Time, error) {
+	t, err := time.Parse(dateLayout+" "+timeLayout, receipt.PurchaseDate+" "+receipt.PurchaseTime)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid purchase date or time: %w", err)
+	}
+	return t, nil
+}
@@ -0,0 +1,60 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"receipt-processor/models"
+)
+
+func TestLateSubmissionPenalty(t *testing.T) {
+	cfg := models.DefaultRuleConfig()
+	cfg.MaxSubmissionDelay = 7 * 24 * time.Hour
+	cfg.LateSubmissionPenalty = 20
+
+	// targetReceipt was purchased 2022-01-01 13:01 and scores 28.
+	purchased := time.Date(2022, 1, 1, 13, 1, 0, 0, time.UTC)
+	tests := []struct {
+		name      string
+		submitted time.Time
+		want      int
+	}{
+		{"same day", purchased.Add(time.Hour), 28},
+		{"exactly at the limit", purchased.Add(cfg.MaxSubmissionDelay), 28},
+		{"late", purchased.Add(cfg.MaxSubmissionDelay + time.Minute), 8},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewProcessor(NewMapStore())
+			p.Rules = cfg
+			p.Clock = FixedClock(tt.submitted)
+
+			id, err := p.Process(targetReceipt())
+			if err != nil {
+				t.Fatalf("Process: %v", err)
+			}
+			entry, err := p.Store.Get(id)
+			if err != nil {
+				t.Fatalf("Get: %v", err)
+			}
+			if entry.Points != tt.want {
+				t.Errorf("points = %d, want %d", entry.Points, tt.want)
+			}
+		})
+	}
+}
+
+func TestLateSubmissionPenaltyClampsAtZero(t *testing.T) {
+	cfg := models.DefaultRuleConfig()
+	cfg.MaxSubmissionDelay = time.Hour
+	cfg.LateSubmissionPenalty = 100
+
+	got, err := CalculatePointsAt(targetReceipt(), cfg, time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("CalculatePointsAt: %v", err)
+	}
+	if got != 0 {
+		t.Errorf("points = %d, want 0", got)
+	}
+}
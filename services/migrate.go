@@ -0,0 +1,99 @@
+package services
+
+import (
+	"fmt"
+	"sort"
+
+	"receipt-processor/models"
+)
+
+// IDCollision lists stored keys whose receipts all map to NewID under the
+// current ID scheme. IDs may include NewID itself when an entry is already
+// stored under it.
+type IDCollision struct {
+	NewID string
+	IDs   []string
+}
+
+// MigrationError reports the collisions MigrateIDs left unmigrated.
+type MigrationError struct {
+	Collisions []IDCollision
+}
+
+func (e *MigrationError) Error() string {
+	return fmt.Sprintf("%d ID collision(s) left unmigrated", len(e.Collisions))
+}
+
+// MigrateIDs is MigrateIDsWithFormat for hex IDs.
+func MigrateIDs(store ReceiptStore) (migrated int, err error) {
+	return MigrateIDsWithFormat(store, IDFormatHex)
+}
+
+// MigrateIDsWithFormat re-keys every stored entry whose key is not its
+// receipt's ID under CurrentIDScheme to that ID rendered in format, removing
+// the old key. Keys that already match under CurrentIDScheme in any format
+// are left alone, so IDs handed out by a Processor with another IDFormat
+// keep working. Entries that would collide on the same new ID are left in
+// place and reported in a *MigrationError; all other entries are still
+// migrated.
+func MigrateIDsWithFormat(store ReceiptStore, format IDFormat) (migrated int, err error) {
+	entries := make(map[string]models.StoredReceipt)
+	targets := make(map[string][]string)
+	var computeErr error
+	err = store.Range(func(id string, entry models.StoredReceipt) bool {
+		entries[id] = entry
+		current, err := keyMatchesReceipt(id, entry.Receipt)
+		if err != nil {
+			computeErr = err
+			return false
+		}
+		newID := id
+		if !current {
+			if newID, err = ComputeReceiptIDWithFormat(entry.Receipt, format); err != nil {
+				computeErr = err
+				return false
+			}
+		}
+		targets[newID] = append(targets[newID], id)
+		return true
+	})
+	if err != nil {
+		return 0, err
+	}
+	if computeErr != nil {
+		return 0, computeErr
+	}
+
+	newIDs := make([]string, 0, len(targets))
+	for newID := range targets {
+		newIDs = append(newIDs, newID)
+	}
+	sort.Strings(newIDs)
+
+	var collisions []IDCollision
+	for _, newID := range newIDs {
+		oldIDs := targets[newID]
+		_, taken := entries[newID]
+		if len(oldIDs) == 1 && oldIDs[0] == newID {
+			continue
+		}
+		if len(oldIDs) > 1 || taken {
+			sort.Strings(oldIDs)
+			collisions = append(collisions, IDCollision{NewID: newID, IDs: oldIDs})
+			continue
+		}
+		oldID := oldIDs[0]
+		if err := store.Set(newID, entries[oldID]); err != nil {
+			return migrated, err
+		}
+		if err := store.Delete(oldID); err != nil {
+			return migrated, err
+		}
+		migrated++
+	}
+
+	if len(collisions) > 0 {
+		return migrated, &MigrationError{Collisions: collisions}
+	}
+	return migrated, nil
+}
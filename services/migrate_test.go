@@ -0,0 +1,114 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"receipt-processor/models"
+)
+
+// storeUnderV1 stores a receipt keyed by its legacy v1 ID.
+func storeUnderV1(t *testing.T, store ReceiptStore, receipt models.Receipt) string {
+	t.Helper()
+	id, err := ComputeReceiptIDWithScheme(receipt, IDSchemeV1)
+	if err != nil {
+		t.Fatalf("ComputeReceiptIDWithScheme: %v", err)
+	}
+	if err := store.Set(id, models.StoredReceipt{Receipt: receipt}); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	return id
+}
+
+func TestMigrateIDs(t *testing.T) {
+	store := NewMapStore()
+	oldTarget := storeUnderV1(t, store, targetReceipt())
+	oldRound := storeUnderV1(t, store, roundReceipt())
+
+	migrated, err := MigrateIDs(store)
+	if err != nil {
+		t.Fatalf("MigrateIDs: %v", err)
+	}
+	if migrated != 2 {
+		t.Errorf("migrated = %d, want 2", migrated)
+	}
+	for _, old := range []string{oldTarget, oldRound} {
+		if ok, _ := store.Has(old); ok {
+			t.Errorf("old key %s still present", old)
+		}
+	}
+	for _, r := range []models.Receipt{targetReceipt(), roundReceipt()} {
+		if ok, _ := store.Has(ComputeReceiptID(r)); !ok {
+			t.Errorf("new key for %s missing", r.Retailer)
+		}
+	}
+
+	migrated, err = MigrateIDs(store)
+	if err != nil || migrated != 0 {
+		t.Errorf("second run = (%d, %v), want (0, nil)", migrated, err)
+	}
+}
+
+func TestMigrateIDsReportsCollisions(t *testing.T) {
+	store := NewMapStore()
+	original := targetReceipt()
+	reordered := targetReceipt()
+	reordered.Items[0], reordered.Items[1] = reordered.Items[1], reordered.Items[0]
+
+	// The two v1 IDs differ, but v2 ignores item order so both map to one ID.
+	oldA := storeUnderV1(t, store, original)
+	oldB := storeUnderV1(t, store, reordered)
+	storeUnderV1(t, store, roundReceipt())
+
+	migrated, err := MigrateIDs(store)
+	if migrated != 1 {
+		t.Errorf("migrated = %d, want 1", migrated)
+	}
+	var merr *MigrationError
+	if !errors.As(err, &merr) {
+		t.Fatalf("error = %v, want *MigrationError", err)
+	}
+	if len(merr.Collisions) != 1 {
+		t.Fatalf("collisions = %+v, want 1", merr.Collisions)
+	}
+	c := merr.Collisions[0]
+	if c.NewID != ComputeReceiptID(original) || len(c.IDs) != 2 {
+		t.Errorf("collision = %+v", c)
+	}
+	for _, old := range []string{oldA, oldB} {
+		if ok, _ := store.Has(old); !ok {
+			t.Errorf("colliding entry %s was dropped", old)
+		}
+	}
+}
+
+func TestMigrateIDsKeepsCurrentKeysInOtherFormats(t *testing.T) {
+	p := NewProcessor(NewMapStore())
+	p.IDFormat = IDFormatBase32
+	id, err := p.Process(targetReceipt())
+	if err != nil {
+		t.Fatal(err)
+	}
+	oldRound := storeUnderV1(t, p.Store, roundReceipt())
+
+	migrated, err := MigrateIDsWithFormat(p.Store, IDFormatBase32)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if migrated != 1 {
+		t.Errorf("migrated = %d, want 1", migrated)
+	}
+	if ok, _ := p.Store.Has(id); !ok {
+		t.Errorf("base32 key %s was migrated away", id)
+	}
+	if ok, _ := p.Store.Has(oldRound); ok {
+		t.Errorf("stale key %s still present", oldRound)
+	}
+	newRound, _ := ComputeReceiptIDWithFormat(roundReceipt(), IDFormatBase32)
+	if ok, _ := p.Store.Has(newRound); !ok {
+		t.Errorf("stale entry not re-keyed to its base32 ID %s", newRound)
+	}
+	if n, _ := CountReceipts(p.Store); n != 2 {
+		t.Errorf("store holds %d entries, want 2", n)
+	}
+}
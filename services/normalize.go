@@ -0,0 +1,47 @@
+package services
+
+import (
+	"strings"
+
+	"receipt-processor/models"
+)
+
+// ValidateAndNormalize validates receipt with ValidateReceipt and returns a
+// normalized copy: retailer and item descriptions trimmed and amounts in
+// canonical form, so "012.50" becomes "12.50". Scoring is unaffected. On a
+// validation error the zero Receipt is returned. receipt is not modified.
+// Processor hashes and stores receipts in this form.
+func ValidateAndNormalize(receipt models.Receipt) (models.Receipt, error) {
+	if err := ValidateReceipt(receipt); err != nil {
+		return models.Receipt{}, err
+	}
+	return normalizeReceipt(receipt), nil
+}
+
+// normalizeReceipt returns the normalized copy of a validated receipt
+// described by ValidateAndNormalize.
+func normalizeReceipt(receipt models.Receipt) models.Receipt {
+	receipt.Retailer = strings.TrimSpace(receipt.Retailer)
+	receipt.Total = canonicalAmount(receipt.Total)
+	receipt.Subtotal = canonicalAmount(receipt.Subtotal)
+	receipt.Tax = canonicalAmount(receipt.Tax)
+	items := make([]models.Item, len(receipt.Items))
+	for i, item := range receipt.Items {
+		item.ShortDescription = strings.TrimSpace(item.ShortDescription)
+		item.Price = canonicalAmount(item.Price)
+		item.Discount = canonicalAmount(item.Discount)
+		items[i] = item
+	}
+	receipt.Items = items
+	return receipt
+}
+
+// canonicalAmount rewrites a validated amount without leading zeros.
+// Empty optional amounts stay empty.
+func canonicalAmount(amount string) string {
+	cents, err := parseCents(amount)
+	if err != nil {
+		return amount
+	}
+	return formatCents(cents)
+}
@@ -0,0 +1,126 @@
+package services
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"receipt-processor/models"
+)
+
+func TestValidateAndNormalize(t *testing.T) {
+	input := models.Receipt{
+		Retailer:     "  Corner Shop ",
+		PurchaseDate: "2022-03-20",
+		PurchaseTime: "14:33",
+		Items: []models.Item{
+			{ShortDescription: " Gatorade  ", Price: "02.25"},
+			{ShortDescription: "Chips", Price: "0.75", SKU: "CH1"},
+		},
+		Total:    "003.00",
+		Subtotal: "2.80",
+		Tax:      "0.20",
+		Notes:    "  kept as given ",
+	}
+	original := input
+	original.Items = append([]models.Item(nil), input.Items...)
+
+	got, err := ValidateAndNormalize(input)
+	if err != nil {
+		t.Fatalf("ValidateAndNormalize: %v", err)
+	}
+	want := models.Receipt{
+		Retailer:     "Corner Shop",
+		PurchaseDate: "2022-03-20",
+		PurchaseTime: "14:33",
+		Items: []models.Item{
+			{ShortDescription: "Gatorade", Price: "2.25"},
+			{ShortDescription: "Chips", Price: "0.75", SKU: "CH1"},
+		},
+		Total:    "3.00",
+		Subtotal: "2.80",
+		Tax:      "0.20",
+		Notes:    "  kept as given ",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("normalized = %+v, want %+v", got, want)
+	}
+	if !reflect.DeepEqual(input, original) {
+		t.Error("ValidateAndNormalize modified its input")
+	}
+
+	inPoints, _ := CalculatePoints(input)
+	outPoints, _ := CalculatePoints(got)
+	if inPoints != outPoints {
+		t.Errorf("normalizing changed the score from %d to %d", inPoints, outPoints)
+	}
+}
+
+func TestValidateAndNormalizeRejectsInvalid(t *testing.T) {
+	receipt := targetReceipt()
+	receipt.Total = " 35.35"
+	got, err := ValidateAndNormalize(receipt)
+	var verr *ValidationError
+	if !errors.As(err, &verr) {
+		t.Fatalf("err = %v, want a *ValidationError", err)
+	}
+	if !reflect.DeepEqual(got, models.Receipt{}) {
+		t.Errorf("returned %+v alongside the error, want the zero Receipt", got)
+	}
+}
+
+func TestProcessorStoresCanonicalForm(t *testing.T) {
+	p := NewProcessor(NewMapStore())
+	padded := targetReceipt()
+	padded.Total = "035.35"
+	padded.Items[0].Price = "06.49"
+
+	id, err := p.Process(padded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	canonical, err := ValidateAndNormalize(padded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id != ComputeReceiptID(canonical) {
+		t.Error("ID is not the hash of the canonical receipt")
+	}
+	entry, _ := p.Store.Get(id)
+	if !reflect.DeepEqual(entry.Receipt, canonical) {
+		t.Errorf("stored %+v, want the canonical %+v", entry.Receipt, canonical)
+	}
+	if _, err := p.Process(canonical); !errors.Is(err, ErrDuplicateReceipt) {
+		t.Errorf("resubmitting the canonical form: err = %v, want ErrDuplicateReceipt", err)
+	}
+}
+
+func TestIDsOfNonCanonicalReceipt(t *testing.T) {
+	store := NewMapStore()
+	padded := targetReceipt()
+	padded.Total = "035.35"
+	padded.Items[0].Price = "06.49"
+	stored, err := ProcessReceipt(padded, store)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if id := ComputeReceiptID(padded); id != stored {
+		t.Errorf("ComputeReceiptID = %s, want %s", id, stored)
+	}
+	if id, err := ComputeReceiptIDWithFormat(padded, IDFormatHex); id != stored || err != nil {
+		t.Errorf("ComputeReceiptIDWithFormat = %s, %v; want %s", id, err, stored)
+	}
+	if ids, err := ComputeReceiptIDs(padded); err != nil || ids[CurrentIDScheme] != stored {
+		t.Errorf("ComputeReceiptIDs = %v, %v; want %s under %s", ids, err, stored, CurrentIDScheme)
+	}
+	if id, err := GenerateReceiptID(padded, store); id != stored || !errors.Is(err, ErrDuplicateReceipt) {
+		t.Errorf("GenerateReceiptID = %s, %v; want %s, ErrDuplicateReceipt", id, err, stored)
+	}
+	if id, err := GenerateReceiptIDWithPolicy(padded, store, ExactHashPolicy{}); id != stored || !errors.Is(err, ErrDuplicateReceipt) {
+		t.Errorf("GenerateReceiptIDWithPolicy = %s, %v; want %s, ErrDuplicateReceipt", id, err, stored)
+	}
+	if id, exists, err := LookupID(padded, store); id != stored || !exists || err != nil {
+		t.Errorf("LookupID = %s, %v, %v; want %s, true", id, exists, err, stored)
+	}
+}
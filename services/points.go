@@ -0,0 +1,459 @@
+package services
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"receipt-processor/models"
+)
+
+const (
+	dateLayout = "2006-01-02"
+	timeLayout = "15:04"
+
+	centsPerDollar  = 100
+	centsPerQuarter = 25
+)
+
+// rule is a single named scoring rule.
+type rule struct {
+	name  string
+	score func(receipt models.Receipt, cfg models.RuleConfig) (int, error)
+}
+
+// apply scores receipt with r unless cfg disables r, or gates r and the
+// gate rejects it.
+func (r rule) apply(receipt models.Receipt, cfg models.RuleConfig) (int, error) {
+	if !ruleEnabled(r.name, cfg) {
+		return 0, nil
+	}
+	if gate := cfg.RuleGates[r.name]; gate != nil && !gate(receipt) {
+		return 0, nil
+	}
+	return r.score(receipt, cfg)
+}
+
+// ruleEnabled reports whether cfg.EnabledRules leaves the named rule on.
+func ruleEnabled(name string, cfg models.RuleConfig) bool {
+	enabled, listed := cfg.EnabledRules[name]
+	return enabled || !listed
+}
+
+// builtinRules are the scoring rules applied by CalculatePoints, in order.
+var builtinRules = []rule{
+	{"retailer_name", retailerNamePoints},
+	{"round_dollar", roundDollarPoints},
+	{"quarter_multiple", quarterMultiplePoints},
+	{"item_pairs", itemPairPoints},
+	{"description_length", descriptionLengthPoints},
+	{"odd_day", oddDayPoints},
+	{"even_day", evenDayPoints},
+	{"time_window", timeWindowPoints},
+}
+
+// defaultRuleConfig is the DefaultRuleConfig used by CalculatePoints, built
+// once so the scoring hot path does not allocate. It must not be modified.
+var defaultRuleConfig = models.DefaultRuleConfig()
+
+// CalculatePoints scores a receipt using DefaultRuleConfig. It does not
+// allocate; use CalculatePointsWithBreakdown when the per-rule points are
+// needed.
+func CalculatePoints(receipt models.Receipt) (int, error) {
+	return CalculatePointsWithConfig(receipt, defaultRuleConfig)
+}
+
+// CalculatePointsWithConfig scores a receipt using the given rule config.
+// The receipt is expected to have passed ValidateReceipt.
+func CalculatePointsWithConfig(receipt models.Receipt, cfg models.RuleConfig) (int, error) {
+	points := 0
+	for _, r := range builtinRules {
+		p, err := r.apply(receipt, cfg)
+		if err != nil {
+			return 0, err
+		}
+		points += p
+	}
+	return finalPoints(points, cfg), nil
+}
+
+// finalPoints turns the sum of the rule points into the final score: it adds
+// cfg.BasePoints, rounds with roundFinal, then applies cfg.MaxPoints.
+func finalPoints(points int, cfg models.RuleConfig) int {
+	return capPoints(roundFinal(points+cfg.BasePoints, cfg), cfg)
+}
+
+// capPoints limits points to cfg.MaxPoints when a cap is set.
+func capPoints(points int, cfg models.RuleConfig) int {
+	if cfg.MaxPoints > 0 && points > cfg.MaxPoints {
+		return cfg.MaxPoints
+	}
+	return points
+}
+
+// roundFinal rounds a summed score to a multiple of cfg.RoundFinalTo.
+func roundFinal(points int, cfg models.RuleConfig) int {
+	m := cfg.RoundFinalTo
+	if m <= 1 {
+		return points
+	}
+	down := points - ((points%m)+m)%m
+	switch cfg.RoundingMode {
+	case models.RoundDown:
+		return down
+	case models.RoundUp:
+		if down == points {
+			return points
+		}
+		return down + m
+	default:
+		if points-down >= m-(points-down) {
+			return down + m
+		}
+		return down
+	}
+}
+
+func retailerNamePoints(receipt models.Receipt, cfg models.RuleConfig) (int, error) {
+	return retailerCharCount(receipt.Retailer, cfg) * cfg.PointsPerRetailerChar, nil
+}
+
+// retailerCharCount returns the number of retailer characters that earn
+// points, after resolving cfg.RetailerAliases.
+func retailerCharCount(retailer string, cfg models.RuleConfig) int {
+	retailer = canonicalRetailer(retailer, cfg)
+	if cfg.LegacyRetailerCount {
+		return len(strings.ReplaceAll(retailer, " ", ""))
+	}
+	return countAlphanumeric(retailer)
+}
+
+// canonicalRetailer returns the alias cfg.RetailerAliases gives retailer, or
+// retailer itself if none applies.
+func canonicalRetailer(retailer string, cfg models.RuleConfig) string {
+	if len(cfg.RetailerAliases) == 0 {
+		return retailer
+	}
+	trimmed := strings.TrimSpace(retailer)
+	if name, ok := cfg.RetailerAliases[trimmed]; ok {
+		return name
+	}
+	patterns := make([]string, 0, len(cfg.RetailerAliases))
+	for pattern := range cfg.RetailerAliases {
+		patterns = append(patterns, pattern)
+	}
+	sort.Strings(patterns)
+	for _, pattern := range patterns {
+		re, err := aliasPattern(pattern)
+		if err == nil && re.MatchString(trimmed) {
+			return cfg.RetailerAliases[pattern]
+		}
+	}
+	return retailer
+}
+
+// aliasPatterns caches compiled RetailerAliases keys.
+var aliasPatterns sync.Map
+
+// aliasPattern compiles a RetailerAliases key to match whole names.
+func aliasPattern(pattern string) (*regexp.Regexp, error) {
+	if re, ok := aliasPatterns.Load(pattern); ok {
+		return re.(*regexp.Regexp), nil
+	}
+	re, err := regexp.Compile(`^(?:` + pattern + `)$`)
+	if err != nil {
+		return nil, err
+	}
+	aliasPatterns.Store(pattern, re)
+	return re, nil
+}
+
+// countAlphanumeric returns the number of letters and digits in s.
+func countAlphanumeric(s string) int {
+	n := 0
+	for _, r := range s {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			n++
+		}
+	}
+	return n
+}
+
+func roundDollarPoints(receipt models.Receipt, cfg models.RuleConfig) (int, error) {
+	round, _, err := totalRulePoints(receipt.Total, cfg)
+	return round, err
+}
+
+func quarterMultiplePoints(receipt models.Receipt, cfg models.RuleConfig) (int, error) {
+	_, quarter, err := totalRulePoints(receipt.Total, cfg)
+	return quarter, err
+}
+
+// totalRulePoints evaluates the round-dollar and quarter-multiple rules
+// together, since MutuallyExclusiveTotalRules makes each depend on the other.
+// When exclusive and both apply, only the higher is kept, with the
+// round-dollar rule winning ties. Non-positive totals earn neither unless
+// cfg.AllowNonPositiveTotalBonuses is set.
+func totalRulePoints(total string, cfg models.RuleConfig) (round, quarter int, err error) {
+	cents, err := parseAmount(total, cfg.DecimalSeparator)
+	if err != nil {
+		return 0, 0, err
+	}
+	if cents <= 0 && !cfg.AllowNonPositiveTotalBonuses {
+		return 0, 0, nil
+	}
+	remainder := cents % centsPerDollar
+	if remainder < 0 {
+		remainder = -remainder
+	}
+	distance := min(remainder, centsPerDollar-remainder)
+	switch {
+	case remainder == 0:
+		round = cfg.RoundDollarPoints
+	case cfg.NearRoundThreshold > 0 && distance <= int64(cfg.NearRoundThreshold):
+		round = cfg.NearRoundBonus
+	}
+	if cents%centsPerQuarter == 0 {
+		quarter = cfg.QuarterMultiplePoints
+	}
+	if cfg.MutuallyExclusiveTotalRules && round > 0 && quarter > 0 {
+		if round >= quarter {
+			quarter = 0
+		} else {
+			round = 0
+		}
+	}
+	return round, quarter, nil
+}
+
+func itemPairPoints(receipt models.Receipt, cfg models.RuleConfig) (int, error) {
+	return itemCountPoints(countedItems(receipt, cfg), cfg), nil
+}
+
+// countedItems returns the number of items that count toward the item-pair rule.
+func countedItems(receipt models.Receipt, cfg models.RuleConfig) int {
+	n := 0
+	for _, item := range receipt.Items {
+		if countsAsItem(item, cfg) {
+			n++
+		}
+	}
+	return n
+}
+
+// countsAsItem reports whether item counts toward the item-pair rule.
+func countsAsItem(item models.Item, cfg models.RuleConfig) bool {
+	return !(cfg.ExcludeTaxItems && item.IsTax)
+}
+
+// itemCountPoints returns the item-pair rule's points for n items.
+func itemCountPoints(n int, cfg models.RuleConfig) int {
+	points := itemGroups(n, cfg) * cfg.PointsPerGroup
+	if cfg.ProratePartialGroup && !cfg.RoundUpPartialGroup && cfg.ItemsPerGroup > 0 {
+		points += cfg.PointsPerGroup * (n % cfg.ItemsPerGroup) / cfg.ItemsPerGroup
+	}
+	return points
+}
+
+// itemGroups returns how many item groups n items make up under cfg.
+func itemGroups(n int, cfg models.RuleConfig) int {
+	if cfg.ItemsPerGroup <= 0 {
+		return 0
+	}
+	groups := n / cfg.ItemsPerGroup
+	if cfg.RoundUpPartialGroup && n%cfg.ItemsPerGroup != 0 {
+		groups++
+	}
+	return groups
+}
+
+func descriptionLengthPoints(receipt models.Receipt, cfg models.RuleConfig) (int, error) {
+	if cfg.DescriptionLengthMultiple <= 0 {
+		return 0, nil
+	}
+	points := 0
+	for _, item := range receipt.Items {
+		// Zero-length descriptions, accepted only with AllowEmptyDescriptions,
+		// would otherwise count as a multiple of every length.
+		n := descriptionLength(item.ShortDescription, cfg.DescriptionLengthMode)
+		if n == 0 || n%cfg.DescriptionLengthMultiple != 0 {
+			continue
+		}
+		cents, err := effectivePrice(item, cfg.DecimalSeparator)
+		if err != nil {
+			return 0, err
+		}
+		itemPoints := capItemPoints(priceMultiplePoints(cents, cfg.DescriptionPriceMultiplier), cfg)
+		if cfg.BestItemOnly {
+			points = max(points, itemPoints)
+		} else {
+			points += itemPoints
+		}
+	}
+	return points, nil
+}
+
+// effectivePrice returns the item's price in cents less its discount, never
+// below zero.
+func effectivePrice(item models.Item, sep string) (int64, error) {
+	cents, err := parseAmount(item.Price, sep)
+	if err != nil || item.Discount == "" {
+		return cents, err
+	}
+	discount, err := parseAmount(item.Discount, sep)
+	if err != nil {
+		return 0, err
+	}
+	return max(cents-discount, 0), nil
+}
+
+// descriptionLength measures a description for the description-length rule.
+func descriptionLength(description string, mode models.LengthMode) int {
+	if mode != models.LengthVisibleRunes {
+		return len(strings.TrimSpace(description))
+	}
+	n := 0
+	for _, r := range description {
+		if !unicode.IsSpace(r) && !unicode.IsControl(r) {
+			n++
+		}
+	}
+	return n
+}
+
+// capItemPoints applies cfg.MaxPerItemPoints to one item's contribution.
+func capItemPoints(points int, cfg models.RuleConfig) int {
+	if cfg.MaxPerItemPoints > 0 && points > cfg.MaxPerItemPoints {
+		return cfg.MaxPerItemPoints
+	}
+	return points
+}
+
+// priceMultiplePoints returns ceil(price * multiplier) for a price in cents.
+// A small epsilon keeps float error from rounding exact results up.
+func priceMultiplePoints(cents int64, multiplier float64) int {
+	return int(math.Ceil(float64(cents)*multiplier/centsPerDollar - 1e-9))
+}
+
+func oddDayPoints(receipt models.Receipt, cfg models.RuleConfig) (int, error) {
+	odd, err := oddPurchaseDay(receipt)
+	if err != nil || !odd {
+		return 0, err
+	}
+	return cfg.OddDayPoints, nil
+}
+
+func evenDayPoints(receipt models.Receipt, cfg models.RuleConfig) (int, error) {
+	odd, err := oddPurchaseDay(receipt)
+	if err != nil || odd {
+		return 0, err
+	}
+	return cfg.EvenDayPoints, nil
+}
+
+// oddPurchaseDay reports whether the receipt was purchased on an odd day of
+// the month.
+func oddPurchaseDay(receipt models.Receipt) (bool, error) {
+	date, err := time.Parse(dateLayout, receipt.PurchaseDate)
+	if err != nil {
+		return false, fmt.Errorf("invalid purchase date: %w", err)
+	}
+	return date.Day()%2 == 1, nil
+}
+
+func timeWindowPoints(receipt models.Receipt, cfg models.RuleConfig) (int, error) {
+	purchase, err := time.Parse(timeLayout, receipt.PurchaseTime)
+	if err != nil {
+		return 0, fmt.Errorf("invalid purchase time: %w", err)
+	}
+	points := 0
+	for _, w := range cfg.TimeWindows {
+		start, err := time.Parse(timeLayout, w.Start)
+		if err != nil {
+			return 0, fmt.Errorf("invalid time window start: %w", err)
+		}
+		end, err := time.Parse(timeLayout, w.End)
+		if err != nil {
+			return 0, fmt.Errorf("invalid time window end: %w", err)
+		}
+		switch {
+		case inWindow(purchase, start, end):
+			points += w.Points
+		case nearWindow(purchase, start, end, cfg.TimeWindowGrace):
+			points += w.Points * cfg.TimeWindowGracePercent / 100
+		}
+	}
+	return points, nil
+}
+
+// nearWindow reports whether t, outside the window, is within grace of its
+// start or end.
+func nearWindow(t, start, end time.Time, grace time.Duration) bool {
+	if grace <= 0 {
+		return false
+	}
+	return clockDistance(t, start) <= grace || clockDistance(end, t) <= grace
+}
+
+// clockDistance returns how long after a the clock next reads b.
+func clockDistance(a, b time.Time) time.Duration {
+	const day = 24 * time.Hour
+	return ((b.Sub(a) % day) + day) % day
+}
+
+// inWindow reports whether t is strictly between start and end. A window
+// whose start is after its end wraps around midnight.
+func inWindow(t, start, end time.Time) bool {
+	if start.After(end) {
+		return t.After(start) || t.Before(end)
+	}
+	return t.After(start) && t.Before(end)
+}
+
+// parseCents converts a decimal amount such as "12.34" into cents.
+func parseCents(amount string) (int64, error) {
+	negative := strings.HasPrefix(amount, "-")
+	whole, frac, _ := strings.Cut(strings.TrimPrefix(amount, "-"), ".")
+	if whole == "" || len(frac) > 2 || !isDigits(whole) || !isDigits(frac) {
+		return 0, fmt.Errorf("invalid amount %q", amount)
+	}
+	dollars, err := strconv.ParseInt(whole, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid amount %q", amount)
+	}
+	cents := dollars * centsPerDollar
+	switch len(frac) {
+	case 1:
+		cents += int64(frac[0]-'0') * 10
+	case 2:
+		cents += int64(frac[0]-'0')*10 + int64(frac[1]-'0')
+	}
+	if negative {
+		cents = -cents
+	}
+	return cents, nil
+}
+
+// formatCents renders an amount in cents as a decimal string like "12.34".
+func formatCents(cents int64) string {
+	sign := ""
+	if cents < 0 {
+		sign, cents = "-", -cents
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, cents/centsPerDollar, cents%centsPerDollar)
+}
+
+func isDigits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
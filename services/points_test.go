@@ -0,0 +1,626 @@
+package services
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"receipt-processor/models"
+)
+
+// targetReceipt is the "Target" example from the API description, worth 28 points.
+func targetReceipt() models.Receipt {
+	return models.Receipt{
+		Retailer:     "Target",
+		PurchaseDate: "2022-01-01",
+		PurchaseTime: "13:01",
+		Items: []models.Item{
+			{ShortDescription: "Mountain Dew 12PK", Price: "6.49"},
+			{ShortDescription: "Emils Cheese Pizza", Price: "12.25"},
+			{ShortDescription: "Knorr Creamy Chicken", Price: "1.26"},
+			{ShortDescription: "Doritos Nacho Cheese", Price: "3.35"},
+			{ShortDescription: "   Klarbrunn 12-PK 12 FL OZ  ", Price: "12.00"},
+		},
+		Total: "35.35",
+	}
+}
+
+// roundReceipt is a round-dollar receipt purchased inside the time window,
+// worth 10 + 50 + 25 + 10 + 10 = 105 points.
+func roundReceipt() models.Receipt {
+	return models.Receipt{
+		Retailer:     "Corner Shop",
+		PurchaseDate: "2022-03-20",
+		PurchaseTime: "14:33",
+		Items: []models.Item{
+			{ShortDescription: "Gatorade", Price: "2.25"},
+			{ShortDescription: "Gatorade", Price: "2.25"},
+			{ShortDescription: "Gatorade", Price: "2.25"},
+			{ShortDescription: "Gatorade", Price: "2.25"},
+		},
+		Total: "9.00",
+	}
+}
+
+func TestCalculatePoints(t *testing.T) {
+	tests := []struct {
+		name    string
+		receipt models.Receipt
+		want    int
+	}{
+		{"target example", targetReceipt(), 28},
+		{"round total in window", roundReceipt(), 105},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := CalculatePoints(tt.receipt)
+			if err != nil {
+				t.Fatalf("CalculatePoints: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("points = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCalculatePointsInvalidTotal(t *testing.T) {
+	receipt := targetReceipt()
+	receipt.Total = "abc"
+	if _, err := CalculatePoints(receipt); err == nil {
+		t.Error("expected error for unparseable total")
+	}
+}
+
+func TestParseCents(t *testing.T) {
+	tests := []struct {
+		in   string
+		want int64
+		ok   bool
+	}{
+		{"12.34", 1234, true},
+		{"0.05", 5, true},
+		{"7", 700, true},
+		{"-1.50", -150, true},
+		{"1.234", 0, false},
+		{"1.-5", 0, false},
+		{"", 0, false},
+		{"abc", 0, false},
+	}
+
+	for _, tt := range tests {
+		got, err := parseCents(tt.in)
+		if (err == nil) != tt.ok {
+			t.Errorf("parseCents(%q) error = %v, want ok=%v", tt.in, err, tt.ok)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("parseCents(%q) = %d, want %d", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestRoundDollarNearRound(t *testing.T) {
+	cfg := models.DefaultRuleConfig()
+	cfg.NearRoundThreshold = 3
+	cfg.NearRoundBonus = 25
+
+	tests := []struct {
+		total string
+		want  int
+	}{
+		{"10.00", 50},
+		{"9.99", 25},
+		{"9.97", 25},
+		{"10.02", 25},
+		{"9.90", 0},
+	}
+
+	for _, tt := range tests {
+		receipt := targetReceipt()
+		receipt.Total = tt.total
+		got, err := roundDollarPoints(receipt, cfg)
+		if err != nil {
+			t.Fatalf("roundDollarPoints(%s): %v", tt.total, err)
+		}
+		if got != tt.want {
+			t.Errorf("roundDollarPoints(%s) = %d, want %d", tt.total, got, tt.want)
+		}
+	}
+}
+
+func TestRoundDollarNearRoundDisabledByDefault(t *testing.T) {
+	receipt := targetReceipt()
+	receipt.Total = "9.99"
+	got, err := roundDollarPoints(receipt, models.DefaultRuleConfig())
+	if err != nil {
+		t.Fatalf("roundDollarPoints: %v", err)
+	}
+	if got != 0 {
+		t.Errorf("points = %d, want 0", got)
+	}
+}
+
+// mmReceipt is the "M&M Corner Market" example from the API description,
+// worth 109 points.
+func mmReceipt() models.Receipt {
+	return models.Receipt{
+		Retailer:     "M&M Corner Market",
+		PurchaseDate: "2022-03-20",
+		PurchaseTime: "14:33",
+		Items: []models.Item{
+			{ShortDescription: "Gatorade", Price: "2.25"},
+			{ShortDescription: "Gatorade", Price: "2.25"},
+			{ShortDescription: "Gatorade", Price: "2.25"},
+			{ShortDescription: "Gatorade", Price: "2.25"},
+		},
+		Total: "9.00",
+	}
+}
+
+func TestRetailerNameCountModes(t *testing.T) {
+	strict := models.DefaultRuleConfig()
+	legacy := models.DefaultRuleConfig()
+	legacy.LegacyRetailerCount = true
+
+	tests := []struct {
+		name string
+		cfg  models.RuleConfig
+		want int
+	}{
+		{"alphanumeric only", strict, 14},
+		{"legacy non-space count", legacy, 15},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := retailerNamePoints(mmReceipt(), tt.cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("retailer points = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRetailerAliases(t *testing.T) {
+	cfg := models.DefaultRuleConfig()
+	cfg.RetailerAliases = map[string]string{
+		`Target Store #\d+`: "Target",
+		"Tgt":               "Target",
+		`Walgreens.*`:       "Walgreens",
+	}
+
+	tests := []struct {
+		retailer string
+		want     int
+	}{
+		{"Target Store #1234", 6}, // 15 characters without the alias
+		{"  Tgt ", 6},
+		{"Target", 6},
+		{"Target Store", 11}, // patterns must match the whole name
+		{"Walgreens Pharmacy", 9},
+	}
+	for _, tt := range tests {
+		receipt := targetReceipt()
+		receipt.Retailer = tt.retailer
+		got, err := retailerNamePoints(receipt, cfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != tt.want {
+			t.Errorf("%q: retailer points = %d, want %d", tt.retailer, got, tt.want)
+		}
+	}
+}
+
+func TestCalculatePointsMMCornerMarket(t *testing.T) {
+	got, err := CalculatePoints(mmReceipt())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != 109 {
+		t.Errorf("points = %d, want 109", got)
+	}
+}
+
+func TestItemGroupRounding(t *testing.T) {
+	receipt := targetReceipt()
+	receipt.Items = receipt.Items[:3]
+
+	roundUp := models.DefaultRuleConfig()
+	roundUp.RoundUpPartialGroup = true
+	triples := models.DefaultRuleConfig()
+	triples.ItemsPerGroup = 3
+	triples.PointsPerGroup = 8
+	prorate := models.DefaultRuleConfig()
+	prorate.ProratePartialGroup = true
+	prorateBoth := roundUp
+	prorateBoth.ProratePartialGroup = true
+
+	tests := []struct {
+		name string
+		cfg  models.RuleConfig
+		want int
+	}{
+		{"round down", models.DefaultRuleConfig(), 5},
+		{"round up", roundUp, 10},
+		{"groups of three", triples, 8},
+		{"prorated", prorate, 7},
+		{"round up wins over prorating", prorateBoth, 10},
+	}
+	for _, tt := range tests {
+		got, err := itemPairPoints(receipt, tt.cfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != tt.want {
+			t.Errorf("%s: points = %d, want %d", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestRoundFinalTo(t *testing.T) {
+	tests := []struct {
+		name string
+		to   int
+		mode models.RoundingMode
+		want int
+	}{
+		{"no rounding", 1, "", 28},
+		{"zero means no rounding", 0, models.RoundUp, 28},
+		{"up to 5", 5, models.RoundUp, 30},
+		{"down to 5", 5, models.RoundDown, 25},
+		{"nearest 5", 5, models.RoundNearest, 30},
+		{"nearest 10", 10, "", 30},
+		{"nearest 8", 8, "", 32},
+		{"up to an exact multiple", 7, models.RoundUp, 28},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := models.DefaultRuleConfig()
+			cfg.RoundFinalTo = tt.to
+			cfg.RoundingMode = tt.mode
+			got, err := CalculatePointsWithConfig(targetReceipt(), cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("points = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMutuallyExclusiveTotalRules(t *testing.T) {
+	exclusive := models.DefaultRuleConfig()
+	exclusive.MutuallyExclusiveTotalRules = true
+
+	tests := []struct {
+		total string
+		cfg   models.RuleConfig
+		want  int
+	}{
+		{"10.00", models.DefaultRuleConfig(), 75},
+		{"10.00", exclusive, 50},
+		{"10.25", models.DefaultRuleConfig(), 25},
+		{"10.25", exclusive, 25},
+	}
+	for _, tt := range tests {
+		round, quarter, err := totalRulePoints(tt.total, tt.cfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := round + quarter; got != tt.want {
+			t.Errorf("%s (exclusive=%v): total bonuses = %d, want %d",
+				tt.total, tt.cfg.MutuallyExclusiveTotalRules, got, tt.want)
+		}
+	}
+}
+
+func TestMaxPerItemPoints(t *testing.T) {
+	receipt := targetReceipt()
+	receipt.Items = []models.Item{
+		{ShortDescription: "Gold Bar", Price: "0.00"},        // length 8: not eligible
+		{ShortDescription: "Diamond Ring", Price: "9999.99"}, // length 12: 2000 points uncapped
+		{ShortDescription: "Emils Cheese Pizza", Price: "12.25"},
+	}
+
+	capped := models.DefaultRuleConfig()
+	capped.MaxPerItemPoints = 50
+
+	tests := []struct {
+		name string
+		cfg  models.RuleConfig
+		want int
+	}{
+		{"uncapped", models.DefaultRuleConfig(), 2000 + 3},
+		{"capped", capped, 50 + 3},
+	}
+	for _, tt := range tests {
+		got, err := descriptionLengthPoints(receipt, tt.cfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != tt.want {
+			t.Errorf("%s: points = %d, want %d", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestBestItemOnly(t *testing.T) {
+	receipt := targetReceipt()
+	receipt.Items = []models.Item{
+		{ShortDescription: "Emils Cheese Pizza", Price: "12.25"}, // 3 points
+		{ShortDescription: "Diamond Ring", Price: "100.00"},      // 20 points
+		{ShortDescription: "Klarbrunn 12-PK 12 FL OZ", Price: "12.00"},
+	}
+
+	best := models.DefaultRuleConfig()
+	best.BestItemOnly = true
+	bestCapped := best
+	bestCapped.MaxPerItemPoints = 2
+
+	tests := []struct {
+		name string
+		cfg  models.RuleConfig
+		want int
+	}{
+		{"sum", models.DefaultRuleConfig(), 3 + 20 + 3},
+		{"best only", best, 20},
+		{"best after cap", bestCapped, 2},
+	}
+	for _, tt := range tests {
+		got, err := descriptionLengthPoints(receipt, tt.cfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != tt.want {
+			t.Errorf("%s: points = %d, want %d", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestDayParityBonus(t *testing.T) {
+	cfg := models.DefaultRuleConfig()
+	cfg.OddDayPoints = 0
+	cfg.EvenDayPoints = 8
+
+	tests := []struct {
+		date       string
+		defaults   int
+		evenReward int
+	}{
+		{"2022-01-01", 6, 0},
+		{"2022-01-02", 0, 8},
+	}
+	for _, tt := range tests {
+		receipt := targetReceipt()
+		receipt.PurchaseDate = tt.date
+		if got, _ := oddDayPoints(receipt, models.DefaultRuleConfig()); got != tt.defaults {
+			t.Errorf("%s default: points = %d, want %d", tt.date, got, tt.defaults)
+		}
+		if got, _ := evenDayPoints(receipt, cfg); got != tt.evenReward {
+			t.Errorf("%s even-day config: points = %d, want %d", tt.date, got, tt.evenReward)
+		}
+	}
+}
+
+func TestDayParityRulesSwitchSeparately(t *testing.T) {
+	cfg := models.DefaultRuleConfig()
+	cfg.EvenDayPoints = 8
+	cfg.EnabledRules = map[string]bool{"odd_day": false}
+
+	odd := targetReceipt() // 2022-01-01
+	even := targetReceipt()
+	even.PurchaseDate = "2022-01-02"
+	oddPoints, _ := CalculatePointsWithConfig(odd, cfg)
+	evenPoints, _ := CalculatePointsWithConfig(even, cfg)
+	if oddPoints != 28-6 || evenPoints != 28-6+8 {
+		t.Errorf("points = %d odd, %d even; want %d and %d with only odd_day disabled", oddPoints, evenPoints, 28-6, 28-6+8)
+	}
+}
+
+func TestTimeWindowAcrossMidnight(t *testing.T) {
+	cfg := models.DefaultRuleConfig()
+	cfg.TimeWindows = []models.TimeWindow{{Start: "22:00", End: "02:00", Points: 15}}
+
+	tests := []struct {
+		time string
+		want int
+	}{
+		{"23:30", 15},
+		{"01:00", 15},
+		{"12:00", 0},
+		{"22:00", 0}, // bounds stay exclusive
+		{"02:00", 0},
+	}
+	for _, tt := range tests {
+		receipt := targetReceipt()
+		receipt.PurchaseTime = tt.time
+		got, err := timeWindowPoints(receipt, cfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != tt.want {
+			t.Errorf("%s: points = %d, want %d", tt.time, got, tt.want)
+		}
+	}
+}
+
+func TestTimeWindowGrace(t *testing.T) {
+	cfg := models.DefaultRuleConfig()
+	cfg.TimeWindowGrace = 15 * time.Minute
+	cfg.TimeWindowGracePercent = 50
+
+	tests := []struct {
+		time string
+		want int
+	}{
+		{"13:50", 5},  // ramp before the window
+		{"14:00", 5},  // the exclusive bound falls in the ramp
+		{"14:30", 10}, // inside earns the full bonus, not full plus ramp
+		{"16:10", 5},  // ramp after the window
+		{"13:30", 0},
+		{"16:20", 0},
+	}
+	for _, tt := range tests {
+		receipt := targetReceipt()
+		receipt.PurchaseTime = tt.time
+		got, err := timeWindowPoints(receipt, cfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != tt.want {
+			t.Errorf("%s: points = %d, want %d", tt.time, got, tt.want)
+		}
+	}
+
+	cfg.TimeWindows = []models.TimeWindow{{Start: "22:00", End: "02:00", Points: 10}}
+	receipt := targetReceipt()
+	receipt.PurchaseTime = "02:10"
+	if got, _ := timeWindowPoints(receipt, cfg); got != 5 {
+		t.Errorf("02:10 after a midnight window: points = %d, want 5", got)
+	}
+}
+
+func TestDescriptionLengthMode(t *testing.T) {
+	visible := models.DefaultRuleConfig()
+	visible.DescriptionLengthMode = models.LengthVisibleRunes
+
+	tests := []struct {
+		description string
+		byBytes     int
+		byVisible   int
+	}{
+		{"Crème", 2, 0},      // 6 bytes, 5 visible runes
+		{"Café Latte", 0, 2}, // 11 bytes, 9 visible runes
+		{" Tea Bag ", 0, 2},  // 7 trimmed bytes, 6 visible runes
+		{"Gatorade", 0, 0},   // 8 either way
+	}
+	for _, tt := range tests {
+		receipt := targetReceipt()
+		receipt.Items = []models.Item{{ShortDescription: tt.description, Price: "10.00"}}
+		if got, _ := descriptionLengthPoints(receipt, models.DefaultRuleConfig()); got != tt.byBytes {
+			t.Errorf("%q by bytes: points = %d, want %d", tt.description, got, tt.byBytes)
+		}
+		if got, _ := descriptionLengthPoints(receipt, visible); got != tt.byVisible {
+			t.Errorf("%q by visible runes: points = %d, want %d", tt.description, got, tt.byVisible)
+		}
+	}
+}
+
+func TestBasePointsAndMaxPoints(t *testing.T) {
+	tests := []struct {
+		name       string
+		base, max  int
+		want       int
+		wantBreaks []models.Contribution
+	}{
+		{"base added", 15, 0, 28 + 15, []models.Contribution{{Rule: "base_points", Points: 15}}},
+		{"base under cap", 15, 50, 28 + 15, []models.Contribution{{Rule: "base_points", Points: 15}}},
+		{"base capped", 15, 40, 40, []models.Contribution{
+			{Rule: "base_points", Points: 15}, {Rule: "max_points", Points: -3}}},
+		{"cap without base", 0, 20, 20, []models.Contribution{{Rule: "max_points", Points: -8}}},
+	}
+	for _, tt := range tests {
+		cfg := models.DefaultRuleConfig()
+		cfg.BasePoints = tt.base
+		cfg.MaxPoints = tt.max
+
+		got, err := CalculatePointsWithConfig(targetReceipt(), cfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != tt.want {
+			t.Errorf("%s: points = %d, want %d", tt.name, got, tt.want)
+		}
+		total, breakdown, _ := CalculatePointsWithBreakdown(targetReceipt(), cfg)
+		adjustments := breakdown[len(builtinRules):]
+		if total != tt.want || !reflect.DeepEqual(adjustments, tt.wantBreaks) {
+			t.Errorf("%s: breakdown total %d with adjustments %+v, want %d and %+v",
+				tt.name, total, adjustments, tt.want, tt.wantBreaks)
+		}
+	}
+}
+
+func TestNonPositiveTotalBonuses(t *testing.T) {
+	allow := models.DefaultRuleConfig()
+	allow.AllowNonPositiveTotalBonuses = true
+
+	tests := []struct {
+		total       string
+		byDefault   int
+		whenAllowed int
+	}{
+		{"0.00", 0, 75},
+		{"-5.00", 0, 75},
+		{"-2.25", 0, 25},
+		{"5.00", 75, 75},
+	}
+	for _, tt := range tests {
+		for _, c := range []struct {
+			name string
+			cfg  models.RuleConfig
+			want int
+		}{{"default", models.DefaultRuleConfig(), tt.byDefault}, {"allowed", allow, tt.whenAllowed}} {
+			round, quarter, err := totalRulePoints(tt.total, c.cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if round+quarter != c.want {
+				t.Errorf("%s %s: bonuses = %d, want %d", tt.total, c.name, round+quarter, c.want)
+			}
+		}
+	}
+}
+
+func TestCalculatePointsDoesNotAllocate(t *testing.T) {
+	receipt := mmReceipt()
+	allocs := testing.AllocsPerRun(100, func() {
+		if _, err := CalculatePoints(receipt); err != nil {
+			t.Fatal(err)
+		}
+	})
+	if allocs != 0 {
+		t.Errorf("CalculatePoints allocated %v times per run, want 0", allocs)
+	}
+}
+
+func BenchmarkCalculatePoints(b *testing.B) {
+	receipt := mmReceipt()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := CalculatePoints(receipt); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkCalculatePointsWithBreakdown(b *testing.B) {
+	receipt := mmReceipt()
+	cfg := models.DefaultRuleConfig()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, _, err := CalculatePointsWithBreakdown(receipt, cfg); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestProratePartialGroupOfThree(t *testing.T) {
+	cfg := models.DefaultRuleConfig()
+	cfg.ProratePartialGroup = true
+	cfg.ItemsPerGroup = 3
+	cfg.PointsPerGroup = 10
+
+	receipt := targetReceipt()
+	receipt.Items = receipt.Items[:5] // one group of three plus two thirds of a group
+	if got, _ := itemPairPoints(receipt, cfg); got != 16 {
+		t.Errorf("points = %d, want 10 + floor(20/3) = 16", got)
+	}
+}
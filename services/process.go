@@ -0,0 +1,178 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"receipt-processor/models"
+)
+
+// Processor runs the submission flow against a store under a rule config.
+type Processor struct {
+	Store      ReceiptStore
+	Rules      models.RuleConfig
+	Validation models.ValidationConfig
+	// Clock supplies the submission time used for time-dependent rules and
+	// the dedup window.
+	Clock Clock
+	// DedupWindow is how long after a receipt is stored that resubmitting it
+	// is rejected as a duplicate. Once it has passed, resubmission succeeds
+	// and returns the original ID without replacing the stored entry. Zero
+	// rejects resubmission for as long as the entry is stored. Retention of
+	// the entry itself is up to the store; see RetentionStore.
+	DedupWindow time.Duration
+	// IDFormat selects how receipt IDs are rendered. The zero value is hex.
+	IDFormat IDFormat
+	// StrictIDs makes ValidateID accept only IDs shaped like those IDFormat
+	// renders, instead of any non-empty token without whitespace.
+	StrictIDs bool
+	// Duplicates decides whether a submission is a duplicate. Nil means
+	// ExactHashPolicy.
+	Duplicates DuplicatePolicy
+	// TotalBonusOncePerDay withholds the round-dollar and quarter-multiple
+	// bonuses from a receipt when the store already holds one from the same
+	// retailer on the same date that earned either. It scans the store on
+	// every submission.
+	TotalBonusOncePerDay bool
+	// Logger, if set, receives a record per submission. Wrap its handler in
+	// a TraceLogHandler to have records carry the trace ID.
+	Logger *slog.Logger
+	// Audit, if set, is called with the outcome of every submission.
+	Audit func(ctx context.Context, entry models.AuditEntry)
+}
+
+// NewProcessor returns a Processor using the default rule and validation
+// configs and the system clock.
+func NewProcessor(store ReceiptStore) *Processor {
+	return &Processor{
+		Store:      store,
+		Rules:      models.DefaultRuleConfig(),
+		Validation: models.DefaultValidationConfig(),
+		Clock:      SystemClock,
+	}
+}
+
+// Process validates, normalizes, deduplicates, scores and stores a receipt,
+// returning the ID it was stored under. The ID and the stored receipt are
+// those of the normalized form; see ValidateAndNormalize.
+func (p *Processor) Process(receipt models.Receipt) (string, error) {
+	return p.ProcessContext(context.Background(), receipt)
+}
+
+// ProcessContext is Process with a request context. The trace ID carried by
+// ctx, or a generated one if it has none, is attached to the log record,
+// the audit entry and any returned error.
+func (p *Processor) ProcessContext(ctx context.Context, receipt models.Receipt) (string, error) {
+	ctx, traceID := ensureTraceID(ctx)
+	now := p.Clock()
+	id, points, err := p.process(receipt, now)
+
+	if p.Logger != nil {
+		if err != nil {
+			p.Logger.WarnContext(ctx, "receipt rejected", "error", err)
+		} else {
+			p.Logger.InfoContext(ctx, "receipt processed", "id", id, "points", points)
+		}
+	}
+	if p.Audit != nil {
+		entry := models.AuditEntry{TraceID: traceID, ReceiptID: id, Points: points, At: now}
+		if err != nil {
+			entry.Error = err.Error()
+		}
+		p.Audit(ctx, entry)
+	}
+	if err != nil {
+		return id, fmt.Errorf("trace %s: %w", traceID, err)
+	}
+	return id, nil
+}
+
+// Validate checks a receipt exactly as Process would at the time given by
+// p.Clock, without generating an ID or touching the store.
+func (p *Processor) Validate(receipt models.Receipt) error {
+	_, err := p.validate(receipt, p.Clock())
+	return err
+}
+
+// validate applies p.Validation's amount handling to a receipt and
+// validates the result at now, returning the receipt as validated.
+func (p *Processor) validate(receipt models.Receipt, now time.Time) (models.Receipt, error) {
+	if p.Validation.TrimTotals {
+		receipt = trimAmounts(receipt)
+	}
+	receipt = NormalizeDecimalSeparator(receipt, p.Validation.DecimalSeparator)
+	validation := p.Validation
+	if validation.Now == nil {
+		validation.Now = func() time.Time { return now }
+	}
+	if err := ValidateReceiptWithConfig(receipt, validation); err != nil {
+		return receipt, err
+	}
+	return receipt, nil
+}
+
+func (p *Processor) process(receipt models.Receipt, now time.Time) (string, int, error) {
+	receipt, err := p.validate(receipt, now)
+	if err != nil {
+		return "", 0, err
+	}
+	receipt = normalizeReceipt(receipt)
+	id, err := generateReceiptID(receipt, p.Store, p.IDFormat, p.Duplicates)
+	if errors.Is(err, ErrDuplicateReceipt) {
+		return id, 0, p.checkDedupWindow(id, now)
+	}
+	if err != nil {
+		return id, 0, err
+	}
+	rules, err := p.scoringRules(id, receipt)
+	if err != nil {
+		return "", 0, err
+	}
+	points, breakdown, err := breakdownAt(receipt, rules, now)
+	if err != nil {
+		return "", 0, err
+	}
+	entry := models.StoredReceipt{Receipt: receipt, Points: points, Breakdown: breakdown, StoredAt: now}
+	if err := p.Store.Set(id, entry); err != nil {
+		return "", 0, err
+	}
+	return id, points, nil
+}
+
+// scoringRules returns the rule config the receipt stored, or about to be
+// stored, under id is scored with: p.Rules, with the total bonuses gated off
+// when TotalBonusOncePerDay applies.
+func (p *Processor) scoringRules(id string, receipt models.Receipt) (models.RuleConfig, error) {
+	if !p.TotalBonusOncePerDay {
+		return p.Rules, nil
+	}
+	return withholdRepeatTotalBonuses(id, receipt, p.Store, p.Rules)
+}
+
+// checkDedupWindow decides whether a resubmission of the stored receipt id
+// is still a duplicate at now. A duplicate policy may report a duplicate
+// with nothing stored under id; that stays a duplicate.
+func (p *Processor) checkDedupWindow(id string, now time.Time) error {
+	if p.DedupWindow <= 0 {
+		return ErrDuplicateReceipt
+	}
+	entry, err := p.Store.Get(id)
+	if errors.Is(err, ErrNotFound) {
+		return ErrDuplicateReceipt
+	}
+	if err != nil {
+		return err
+	}
+	if now.Sub(entry.StoredAt) < p.DedupWindow {
+		return ErrDuplicateReceipt
+	}
+	return nil
+}
+
+// ProcessReceipt processes a receipt with the default Processor for store.
+func ProcessReceipt(receipt models.Receipt, store ReceiptStore) (string, error) {
+	return NewProcessor(store).Process(receipt)
+}
@@ -0,0 +1,21 @@
+package services
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestProcessReceiptRejectsDuplicate(t *testing.T) {
+	store := NewMapStore()
+	first, err := ProcessReceipt(targetReceipt(), store)
+	if err != nil {
+		t.Fatalf("ProcessReceipt: %v", err)
+	}
+	second, err := ProcessReceipt(targetReceipt(), store)
+	if !errors.Is(err, ErrDuplicateReceipt) {
+		t.Fatalf("error = %v, want ErrDuplicateReceipt", err)
+	}
+	if second != first {
+		t.Errorf("duplicate ID = %q, want %q", second, first)
+	}
+}
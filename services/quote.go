@@ -0,0 +1,24 @@
+package services
+
+import "receipt-processor/models"
+
+// Quote validates and scores a receipt as Process would at the time given
+// by p.Clock, returning the points it would be awarded and their breakdown.
+// No ID is reserved and nothing is stored.
+func (p *Processor) Quote(receipt models.Receipt) (int, []models.Contribution, error) {
+	now := p.Clock()
+	receipt, err := p.validate(receipt, now)
+	if err != nil {
+		return 0, nil, err
+	}
+	receipt = normalizeReceipt(receipt)
+	id, err := ComputeReceiptIDWithFormat(receipt, p.IDFormat)
+	if err != nil {
+		return 0, nil, err
+	}
+	rules, err := p.scoringRules(id, receipt)
+	if err != nil {
+		return 0, nil, err
+	}
+	return breakdownAt(receipt, rules, now)
+}
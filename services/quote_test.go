@@ -0,0 +1,36 @@
+package services
+
+import "testing"
+
+func TestProcessorQuoteMatchesProcess(t *testing.T) {
+	p := NewProcessor(NewMapStore())
+	p.Validation.DecimalSeparator = ","
+	p.Rules.DecimalSeparator = ","
+	p.TotalBonusOncePerDay = true
+	if _, err := p.Process(roundReceipt()); err != nil {
+		t.Fatal(err)
+	}
+
+	second := roundReceipt()
+	second.Items = second.Items[:2]
+	for i := range second.Items {
+		second.Items[i].Price = "2,25"
+	}
+	second.Total = "04,50"
+	points, breakdown, err := p.Quote(second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n, _ := CountReceipts(p.Store); n != 1 {
+		t.Errorf("store holds %d receipts after a quote, want 1", n)
+	}
+
+	id, err := p.Process(second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	entry, _ := p.Store.Get(id)
+	if points != entry.Points || breakdownSum(breakdown) != points {
+		t.Errorf("quote = %d with breakdown %+v, want the processed %d", points, breakdown, entry.Points)
+	}
+}
@@ -0,0 +1,54 @@
+package services
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"receipt-processor/models"
+)
+
+// UnparseableDateError lists stored receipts skipped because their purchase
+// date could not be parsed.
+type UnparseableDateError struct {
+	IDs []string
+}
+
+func (e *UnparseableDateError) Error() string {
+	return fmt.Sprintf("%d stored receipt(s) have an unparseable purchase date", len(e.IDs))
+}
+
+// SumPointsInRange sums the points of stored receipts purchased between the
+// calendar days of from and to, both inclusive. Receipts whose dates cannot
+// be parsed are left out of the sum and reported in an
+// *UnparseableDateError, returned together with the sum of the rest.
+func SumPointsInRange(store ReceiptStore, from, to time.Time) (int, error) {
+	first, last := calendarDay(from), calendarDay(to)
+	sum := 0
+	var bad []string
+	err := store.Range(func(id string, entry models.StoredReceipt) bool {
+		date, err := time.Parse(dateLayout, entry.Receipt.PurchaseDate)
+		if err != nil {
+			bad = append(bad, id)
+			return true
+		}
+		if !date.Before(first) && !date.After(last) {
+			sum += entry.Points
+		}
+		return true
+	})
+	if err != nil {
+		return 0, err
+	}
+	if bad != nil {
+		sort.Strings(bad)
+		return sum, &UnparseableDateError{IDs: bad}
+	}
+	return sum, nil
+}
+
+// calendarDay returns midnight UTC of t's date, comparable with parsed
+// purchase dates.
+func calendarDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,55 @@
+package services
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"receipt-processor/models"
+)
+
+func TestSumPointsInRange(t *testing.T) {
+	store := NewMapStore()
+	for id, date := range map[string]string{
+		"before":    "2022-02-28",
+		"first-day": "2022-03-01",
+		"mid-month": "2022-03-15",
+		"last-day":  "2022-03-31",
+		"after":     "2022-04-01",
+	} {
+		receipt := targetReceipt()
+		receipt.PurchaseDate = date
+		store.Set(id, models.StoredReceipt{Receipt: receipt, Points: 10})
+	}
+	store.Set("first-day-2", models.StoredReceipt{Receipt: models.Receipt{PurchaseDate: "2022-03-01"}, Points: 5})
+
+	from := time.Date(2022, time.March, 1, 15, 30, 0, 0, time.UTC)
+	to := time.Date(2022, time.March, 31, 0, 0, 0, 0, time.UTC)
+	sum, err := SumPointsInRange(store, from, to)
+	if err != nil {
+		t.Fatalf("SumPointsInRange: %v", err)
+	}
+	if sum != 35 {
+		t.Errorf("sum = %d, want 35 from the three March receipts at 10 and one at 5", sum)
+	}
+}
+
+func TestSumPointsInRangeReportsBadDates(t *testing.T) {
+	store := NewMapStore()
+	store.Set("good", models.StoredReceipt{Receipt: models.Receipt{PurchaseDate: "2022-03-10"}, Points: 7})
+	store.Set("bad", models.StoredReceipt{Receipt: models.Receipt{PurchaseDate: "03/10/2022"}, Points: 100})
+
+	day := time.Date(2022, time.March, 10, 0, 0, 0, 0, time.UTC)
+	sum, err := SumPointsInRange(store, day, day)
+	var dateErr *UnparseableDateError
+	if !errors.As(err, &dateErr) {
+		t.Fatalf("err = %v, want *UnparseableDateError", err)
+	}
+	if !reflect.DeepEqual(dateErr.IDs, []string{"bad"}) {
+		t.Errorf("IDs = %v, want [bad]", dateErr.IDs)
+	}
+	if sum != 7 {
+		t.Errorf("sum = %d, want 7 from the parseable receipt", sum)
+	}
+}
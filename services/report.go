@@ -0,0 +1,79 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+
+	"receipt-processor/models"
+)
+
+// reportLabels describe each built-in rule for FormatScoringReport.
+var reportLabels = map[string]func(receipt models.Receipt, cfg models.RuleConfig) string{
+	"retailer_name": func(r models.Receipt, cfg models.RuleConfig) string {
+		return fmt.Sprintf("Retailer name (%d chars)", retailerCharCount(r.Retailer, cfg))
+	},
+	"round_dollar": func(r models.Receipt, cfg models.RuleConfig) string {
+		cents, _ := parseAmount(r.Total, cfg.DecimalSeparator)
+		if cents%centsPerDollar != 0 {
+			return fmt.Sprintf("Total %s is close to a round dollar", r.Total)
+		}
+		return fmt.Sprintf("Total %s is a round dollar amount", r.Total)
+	},
+	"quarter_multiple": func(r models.Receipt, cfg models.RuleConfig) string {
+		return fmt.Sprintf("Total %s is a multiple of 0.25", r.Total)
+	},
+	"item_pairs": func(r models.Receipt, cfg models.RuleConfig) string {
+		n := countedItems(r, cfg)
+		if cfg.ItemsPerGroup == 2 {
+			return fmt.Sprintf("%d items (%d pairs)", n, itemGroups(n, cfg))
+		}
+		return fmt.Sprintf("%d items (%d groups of %d)", n, itemGroups(n, cfg), cfg.ItemsPerGroup)
+	},
+	"description_length": func(r models.Receipt, cfg models.RuleConfig) string {
+		return fmt.Sprintf("Item descriptions with a length that is a multiple of %d", cfg.DescriptionLengthMultiple)
+	},
+	"odd_day": func(r models.Receipt, cfg models.RuleConfig) string {
+		return fmt.Sprintf("Purchased on an odd day (%s)", r.PurchaseDate)
+	},
+	"even_day": func(r models.Receipt, cfg models.RuleConfig) string {
+		return fmt.Sprintf("Purchased on an even day (%s)", r.PurchaseDate)
+	},
+	"time_window": func(r models.Receipt, cfg models.RuleConfig) string {
+		if cfg.TimeWindowGrace > 0 {
+			return fmt.Sprintf("Purchased during or near a bonus time window (%s)", r.PurchaseTime)
+		}
+		return fmt.Sprintf("Purchased during a bonus time window (%s)", r.PurchaseTime)
+	},
+	basePointsRule: func(r models.Receipt, cfg models.RuleConfig) string {
+		return "Participation bonus"
+	},
+	roundingRule: func(r models.Receipt, cfg models.RuleConfig) string {
+		return fmt.Sprintf("Rounded to a multiple of %d", cfg.RoundFinalTo)
+	},
+	maxPointsRule: func(r models.Receipt, cfg models.RuleConfig) string {
+		return fmt.Sprintf("Capped at %d points", cfg.MaxPoints)
+	},
+}
+
+// FormatScoringReport renders a receipt's breakdown for customers: one line
+// per rule that awarded points, such as "Retailer name (6 chars): +6",
+// followed by "Total: 28 points."
+func FormatScoringReport(receipt models.Receipt, cfg models.RuleConfig) (string, error) {
+	total, breakdown, err := CalculatePointsWithBreakdown(receipt, cfg)
+	if err != nil {
+		return "", err
+	}
+	var b strings.Builder
+	for _, c := range breakdown {
+		if c.Points == 0 {
+			continue
+		}
+		label := c.Rule
+		if describe, ok := reportLabels[c.Rule]; ok {
+			label = describe(receipt, cfg)
+		}
+		fmt.Fprintf(&b, "%s: %+d\n", label, c.Points)
+	}
+	fmt.Fprintf(&b, "Total: %d points.", total)
+	return b.String(), nil
+}
@@ -0,0 +1,33 @@
+package services
+
+import (
+	"strings"
+	"testing"
+
+	"receipt-processor/models"
+)
+
+func TestFormatScoringReport(t *testing.T) {
+	report, err := FormatScoringReport(targetReceipt(), models.DefaultRuleConfig())
+	if err != nil {
+		t.Fatalf("FormatScoringReport: %v", err)
+	}
+
+	for _, line := range []string{
+		"Retailer name (6 chars): +6",
+		"5 items (2 pairs): +10",
+		"Item descriptions with a length that is a multiple of 3: +6",
+		"Purchased on an odd day (2022-01-01): +6",
+		"Total: 28 points.",
+	} {
+		if !strings.Contains(report, line) {
+			t.Errorf("report missing %q:\n%s", line, report)
+		}
+	}
+	if strings.Contains(report, "round dollar") || strings.Contains(report, "time window") {
+		t.Errorf("report includes rules that awarded nothing:\n%s", report)
+	}
+	if !strings.HasSuffix(report, "Total: 28 points.") {
+		t.Errorf("report should end with the total:\n%s", report)
+	}
+}
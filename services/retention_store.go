@@ -0,0 +1,86 @@
+package services
+
+import (
+	"errors"
+	"time"
+
+	"receipt-processor/models"
+)
+
+// RetentionStore hides entries once they are older than the retention
+// period, measured from StoredReceipt.StoredAt. Entries without a StoredAt
+// never expire. Reads never modify the inner store, so it can sit under a
+// ConcurrentStore; expired entries are removed by PurgeExpired.
+type RetentionStore struct {
+	inner     ReceiptStore
+	retention time.Duration
+	clock     Clock
+}
+
+// NewRetentionStore wraps inner so that entries expire retention after they
+// were stored.
+func NewRetentionStore(inner ReceiptStore, retention time.Duration, clock Clock) *RetentionStore {
+	return &RetentionStore{inner: inner, retention: retention, clock: clock}
+}
+
+func (s *RetentionStore) expired(entry models.StoredReceipt) bool {
+	return !entry.StoredAt.IsZero() && s.clock().Sub(entry.StoredAt) >= s.retention
+}
+
+// Get returns ErrNotFound for expired entries.
+func (s *RetentionStore) Get(id string) (models.StoredReceipt, error) {
+	entry, err := s.inner.Get(id)
+	if err != nil {
+		return models.StoredReceipt{}, err
+	}
+	if s.expired(entry) {
+		return models.StoredReceipt{}, ErrNotFound
+	}
+	return entry, nil
+}
+
+func (s *RetentionStore) Set(id string, entry models.StoredReceipt) error {
+	return s.inner.Set(id, entry)
+}
+
+func (s *RetentionStore) Has(id string) (bool, error) {
+	_, err := s.Get(id)
+	if errors.Is(err, ErrNotFound) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+func (s *RetentionStore) Delete(id string) error {
+	return s.inner.Delete(id)
+}
+
+// Range skips expired entries without removing them; see PurgeExpired.
+func (s *RetentionStore) Range(fn func(id string, entry models.StoredReceipt) bool) error {
+	return s.inner.Range(func(id string, entry models.StoredReceipt) bool {
+		if s.expired(entry) {
+			return true
+		}
+		return fn(id, entry)
+	})
+}
+
+// PurgeExpired deletes every expired entry and returns how many were removed.
+func (s *RetentionStore) PurgeExpired() (int, error) {
+	var ids []string
+	err := s.inner.Range(func(id string, entry models.StoredReceipt) bool {
+		if s.expired(entry) {
+			ids = append(ids, id)
+		}
+		return true
+	})
+	if err != nil {
+		return 0, err
+	}
+	for i, id := range ids {
+		if err := s.inner.Delete(id); err != nil {
+			return i, err
+		}
+	}
+	return len(ids), nil
+}
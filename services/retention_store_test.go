@@ -0,0 +1,137 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"receipt-processor/models"
+)
+
+func TestDedupWindowShorterThanRetention(t *testing.T) {
+	now := time.Date(2022, 1, 1, 14, 0, 0, 0, time.UTC)
+	clock := func() time.Time { return now }
+
+	p := NewProcessor(NewRetentionStore(NewMapStore(), 30*24*time.Hour, clock))
+	p.Clock = clock
+	p.DedupWindow = 10 * time.Minute
+
+	id, err := p.Process(targetReceipt())
+	if err != nil {
+		t.Fatalf("Process: %v", err)
+	}
+
+	now = now.Add(5 * time.Minute)
+	if _, err := p.Process(targetReceipt()); !errors.Is(err, ErrDuplicateReceipt) {
+		t.Fatalf("resubmission inside window: error = %v, want ErrDuplicateReceipt", err)
+	}
+
+	now = now.Add(10 * time.Minute)
+	again, err := p.Process(targetReceipt())
+	if err != nil {
+		t.Fatalf("resubmission after window: %v", err)
+	}
+	if again != id {
+		t.Errorf("resubmission ID = %q, want %q", again, id)
+	}
+	entry, err := p.Store.Get(id)
+	if err != nil {
+		t.Fatalf("Get after dedup window: %v", err)
+	}
+	if entry.Points != 28 {
+		t.Errorf("points = %d, want 28", entry.Points)
+	}
+
+	now = now.Add(30 * 24 * time.Hour)
+	if _, err := p.Store.Get(id); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get after retention: error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestDedupWindowZeroBlocksResubmission(t *testing.T) {
+	now := time.Date(2022, 1, 1, 14, 0, 0, 0, time.UTC)
+	p := NewProcessor(NewMapStore())
+	p.Clock = func() time.Time { return now }
+
+	if _, err := p.Process(targetReceipt()); err != nil {
+		t.Fatalf("Process: %v", err)
+	}
+	now = now.Add(365 * 24 * time.Hour)
+	if _, err := p.Process(targetReceipt()); !errors.Is(err, ErrDuplicateReceipt) {
+		t.Errorf("error = %v, want ErrDuplicateReceipt", err)
+	}
+}
+
+func TestRetentionStorePurgeExpired(t *testing.T) {
+	now := time.Date(2022, 1, 1, 14, 0, 0, 0, time.UTC)
+	store := NewRetentionStore(NewMapStore(), time.Hour, func() time.Time { return now })
+
+	p := NewProcessor(store)
+	p.Clock = func() time.Time { return now }
+	if _, err := p.Process(targetReceipt()); err != nil {
+		t.Fatal(err)
+	}
+	now = now.Add(30 * time.Minute)
+	if _, err := p.Process(roundReceipt()); err != nil {
+		t.Fatal(err)
+	}
+
+	now = now.Add(45 * time.Minute)
+	purged, err := store.PurgeExpired()
+	if err != nil {
+		t.Fatalf("PurgeExpired: %v", err)
+	}
+	if purged != 1 {
+		t.Errorf("purged = %d, want 1", purged)
+	}
+	if ok, _ := store.Has(ComputeReceiptID(roundReceipt())); !ok {
+		t.Error("unexpired entry was purged")
+	}
+}
+
+func TestRetentionStoreReadsDoNotDelete(t *testing.T) {
+	now := time.Date(2022, 1, 1, 14, 0, 0, 0, time.UTC)
+	inner := NewMapStore()
+	store := NewRetentionStore(inner, time.Hour, func() time.Time { return now })
+	store.Set("old", models.StoredReceipt{StoredAt: now.Add(-2 * time.Hour)})
+
+	if _, err := store.Get("old"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get expired: error = %v, want ErrNotFound", err)
+	}
+	if ok, _ := store.Has("old"); ok {
+		t.Error("Has reports an expired entry")
+	}
+	if ok, _ := inner.Has("old"); !ok {
+		t.Error("a read removed the expired entry from the inner store")
+	}
+}
+
+func TestRetentionStoreZeroStoredAtNeverExpires(t *testing.T) {
+	store := NewRetentionStore(NewMapStore(), time.Hour, SystemClock)
+	store.Set("probe", models.StoredReceipt{})
+	if _, err := store.Get("probe"); err != nil {
+		t.Errorf("Get without StoredAt: %v", err)
+	}
+}
+
+func TestConcurrentRetentionStore(t *testing.T) {
+	now := time.Date(2022, 1, 1, 14, 0, 0, 0, time.UTC)
+	store := NewConcurrentStore(NewRetentionStore(NewMapStore(), time.Hour, func() time.Time { return now }))
+	store.Set("old", models.StoredReceipt{StoredAt: now.Add(-2 * time.Hour)})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				store.Get("old")
+				store.Has("old")
+				store.Set(fmt.Sprintf("new-%d-%d", i, j), models.StoredReceipt{StoredAt: now})
+			}
+		}(i)
+	}
+	wg.Wait()
+}
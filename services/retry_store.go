@@ -0,0 +1,88 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"receipt-processor/models"
+)
+
+// RetryStore retries failed Get, Set, Has and Delete calls on the store it
+// wraps, for backends with transient errors. ErrNotFound and context errors
+// are returned without retrying. Range is not retried, since fn may already
+// have seen part of the store.
+type RetryStore struct {
+	inner   ReceiptStore
+	retries int
+	backoff time.Duration
+	ctx     context.Context
+}
+
+// NewRetryStore wraps inner so that each failing call is retried up to
+// retries times. The wait before the first retry is backoff, doubling for
+// each retry after it. A negative retries is treated as zero.
+func NewRetryStore(inner ReceiptStore, retries int, backoff time.Duration) *RetryStore {
+	return &RetryStore{inner: inner, retries: max(retries, 0), backoff: backoff, ctx: context.Background()}
+}
+
+// WithContext returns a copy of s whose retries stop once ctx is done, in
+// which case the context's error is returned.
+func (s *RetryStore) WithContext(ctx context.Context) *RetryStore {
+	c := *s
+	c.ctx = ctx
+	return &c
+}
+
+// do calls op until it succeeds, fails with a non-retriable error, or the
+// retry budget or context runs out.
+func (s *RetryStore) do(op func() error) error {
+	wait := s.backoff
+	for attempt := 0; ; attempt++ {
+		err := op()
+		if err == nil || !retriable(err) || attempt == s.retries {
+			return err
+		}
+		timer := time.NewTimer(wait)
+		select {
+		case <-s.ctx.Done():
+			timer.Stop()
+			return s.ctx.Err()
+		case <-timer.C:
+		}
+		wait *= 2
+	}
+}
+
+func retriable(err error) bool {
+	return !errors.Is(err, ErrNotFound) &&
+		!errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded)
+}
+
+func (s *RetryStore) Get(id string) (entry models.StoredReceipt, err error) {
+	err = s.do(func() error {
+		entry, err = s.inner.Get(id)
+		return err
+	})
+	return entry, err
+}
+
+func (s *RetryStore) Set(id string, entry models.StoredReceipt) error {
+	return s.do(func() error { return s.inner.Set(id, entry) })
+}
+
+func (s *RetryStore) Has(id string) (ok bool, err error) {
+	err = s.do(func() error {
+		ok, err = s.inner.Has(id)
+		return err
+	})
+	return ok, err
+}
+
+func (s *RetryStore) Delete(id string) error {
+	return s.do(func() error { return s.inner.Delete(id) })
+}
+
+func (s *RetryStore) Range(fn func(id string, entry models.StoredReceipt) bool) error {
+	return s.inner.Range(fn)
+}
@@ -0,0 +1,100 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"receipt-processor/models"
+)
+
+var errTransient = errors.New("connection reset")
+
+// flakyStore fails the first failures calls to Get and Set with
+// errTransient, then delegates to the embedded store.
+type flakyStore struct {
+	ReceiptStore
+	failures int
+	calls    int
+}
+
+func (s *flakyStore) fail() bool {
+	s.calls++
+	return s.calls <= s.failures
+}
+
+func (s *flakyStore) Get(id string) (models.StoredReceipt, error) {
+	if s.fail() {
+		return models.StoredReceipt{}, errTransient
+	}
+	return s.ReceiptStore.Get(id)
+}
+
+func (s *flakyStore) Set(id string, entry models.StoredReceipt) error {
+	if s.fail() {
+		return errTransient
+	}
+	return s.ReceiptStore.Set(id, entry)
+}
+
+func TestRetryStoreRecoversWithinBudget(t *testing.T) {
+	flaky := &flakyStore{ReceiptStore: NewMapStore(), failures: 2}
+	store := NewRetryStore(flaky, 3, time.Millisecond)
+
+	if err := store.Set("id", models.StoredReceipt{Points: 28}); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	if flaky.calls != 3 {
+		t.Errorf("calls = %d, want 2 failures and a success", flaky.calls)
+	}
+	entry, err := store.Get("id")
+	if err != nil || entry.Points != 28 {
+		t.Errorf("Get = %+v, %v", entry, err)
+	}
+}
+
+func TestRetryStoreGivesUp(t *testing.T) {
+	flaky := &flakyStore{ReceiptStore: NewMapStore(), failures: 5}
+	err := NewRetryStore(flaky, 2, 0).Set("id", models.StoredReceipt{})
+	if !errors.Is(err, errTransient) {
+		t.Errorf("err = %v, want errTransient", err)
+	}
+	if flaky.calls != 3 {
+		t.Errorf("calls = %d, want 1 attempt and 2 retries", flaky.calls)
+	}
+}
+
+func TestRetryStoreNegativeRetries(t *testing.T) {
+	flaky := &flakyStore{ReceiptStore: NewMapStore(), failures: 5}
+	err := NewRetryStore(flaky, -1, 0).Set("id", models.StoredReceipt{})
+	if !errors.Is(err, errTransient) {
+		t.Errorf("err = %v, want errTransient", err)
+	}
+	if flaky.calls != 1 {
+		t.Errorf("calls = %d, want 1 attempt and no retries", flaky.calls)
+	}
+}
+
+func TestRetryStoreDoesNotRetryNotFound(t *testing.T) {
+	flaky := &flakyStore{ReceiptStore: NewMapStore()}
+	if _, err := NewRetryStore(flaky, 3, 0).Get("missing"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("err = %v, want ErrNotFound", err)
+	}
+	if flaky.calls != 1 {
+		t.Errorf("calls = %d, want 1", flaky.calls)
+	}
+}
+
+func TestRetryStoreStopsOnCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	flaky := &flakyStore{ReceiptStore: NewMapStore(), failures: 5}
+	err := NewRetryStore(flaky, 3, time.Hour).WithContext(ctx).Set("id", models.StoredReceipt{})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("err = %v, want context.Canceled", err)
+	}
+	if flaky.calls != 1 {
+		t.Errorf("calls = %d, want 1", flaky.calls)
+	}
+}
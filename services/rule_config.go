@@ -0,0 +1,170 @@
+package services
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"receipt-processor/models"
+)
+
+// LoadRuleConfig decodes a JSON rule config from r. Documents written with
+// an older schemaVersion are migrated first; a document without one is read
+// as version 1, and newer versions are rejected. Fields the
+// document omits keep their DefaultRuleConfig values; unknown fields are
+// rejected. The result is checked with ValidateRuleConfig.
+func LoadRuleConfig(r io.Reader) (models.RuleConfig, error) {
+	var doc map[string]json.RawMessage
+	if err := json.NewDecoder(r).Decode(&doc); err != nil {
+		return models.RuleConfig{}, fmt.Errorf("decode rule config: %w", err)
+	}
+	if doc == nil {
+		doc = make(map[string]json.RawMessage)
+	}
+	if err := migrateRuleConfig(doc); err != nil {
+		return models.RuleConfig{}, err
+	}
+	data, err := json.Marshal(doc)
+	if err != nil {
+		return models.RuleConfig{}, fmt.Errorf("decode rule config: %w", err)
+	}
+
+	cfg := models.DefaultRuleConfig()
+	// Decoding into the default windows would merge the document's windows
+	// into them field by field, so they are only restored when omitted.
+	cfg.TimeWindows = nil
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&cfg); err != nil {
+		return models.RuleConfig{}, fmt.Errorf("decode rule config: %w", err)
+	}
+	if cfg.TimeWindows == nil {
+		cfg.TimeWindows = models.DefaultRuleConfig().TimeWindows
+	}
+	if err := ValidateRuleConfig(cfg); err != nil {
+		return models.RuleConfig{}, err
+	}
+	return cfg, nil
+}
+
+// EncodeRuleConfig writes cfg to w as JSON stamped with the current
+// RuleConfigSchemaVersion, in the form LoadRuleConfig reads.
+func EncodeRuleConfig(w io.Writer, cfg models.RuleConfig) error {
+	cfg.SchemaVersion = models.RuleConfigSchemaVersion
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(cfg)
+}
+
+// ruleConfigMigrations upgrade a serialized rule config by one schema
+// version, indexed by the version they upgrade from. Version 1 is still
+// current, so there are none yet.
+var ruleConfigMigrations = map[int]func(doc map[string]json.RawMessage) error{}
+
+// migrateRuleConfig upgrades doc in place to the current schema version. A
+// document without a schemaVersion is version 1.
+func migrateRuleConfig(doc map[string]json.RawMessage) error {
+	version := 1
+	if raw, ok := doc["schemaVersion"]; ok {
+		if err := json.Unmarshal(raw, &version); err != nil {
+			return fmt.Errorf("decode rule config: schemaVersion: %w", err)
+		}
+	}
+	if version < 1 || version > models.RuleConfigSchemaVersion {
+		return fmt.Errorf("unsupported rule config schema version %d", version)
+	}
+	for ; version < models.RuleConfigSchemaVersion; version++ {
+		if err := ruleConfigMigrations[version](doc); err != nil {
+			return fmt.Errorf("migrate rule config from schema version %d: %w", version, err)
+		}
+	}
+	doc["schemaVersion"] = json.RawMessage(fmt.Sprint(version))
+	return nil
+}
+
+// ValidateRuleConfig reports the first setting in cfg that the scoring rules
+// cannot work with.
+func ValidateRuleConfig(cfg models.RuleConfig) error {
+	switch {
+	case cfg.ItemsPerGroup <= 0:
+		return invalidRuleConfig("itemsPerGroup must be positive")
+	case cfg.DescriptionLengthMultiple <= 0:
+		return invalidRuleConfig("descriptionLengthMultiple must be positive")
+	case cfg.DescriptionPriceMultiplier < 0:
+		return invalidRuleConfig("descriptionPriceMultiplier must not be negative")
+	case cfg.NearRoundThreshold < 0 || cfg.NearRoundThreshold > centsPerDollar/2:
+		return invalidRuleConfig("nearRoundThreshold must be between 0 and 50 cents")
+	case cfg.MaxPerItemPoints < 0:
+		return invalidRuleConfig("maxPerItemPoints must not be negative")
+	case cfg.MaxSubmissionDelay < 0:
+		return invalidRuleConfig("maxSubmissionDelay must not be negative")
+	case cfg.LateSubmissionPenalty < 0:
+		return invalidRuleConfig("lateSubmissionPenalty must not be negative")
+	case cfg.PointsLifetime < 0:
+		return invalidRuleConfig("pointsLifetime must not be negative")
+	case cfg.MaxPoints < 0:
+		return invalidRuleConfig("maxPoints must not be negative")
+	case cfg.TimeWindowGrace < 0:
+		return invalidRuleConfig("timeWindowGrace must not be negative")
+	case cfg.TimeWindowGracePercent < 0 || cfg.TimeWindowGracePercent > 100:
+		return invalidRuleConfig("timeWindowGracePercent must be between 0 and 100")
+	case cfg.RoundFinalTo < 0:
+		return invalidRuleConfig("roundFinalTo must not be negative")
+	}
+	switch cfg.RoundingMode {
+	case "", models.RoundNearest, models.RoundUp, models.RoundDown:
+	default:
+		return invalidRuleConfig(fmt.Sprintf("unknown roundingMode %q", cfg.RoundingMode))
+	}
+	switch cfg.DecimalSeparator {
+	case "", ".", ",":
+	default:
+		return invalidRuleConfig(fmt.Sprintf("unsupported decimalSeparator %q", cfg.DecimalSeparator))
+	}
+	switch cfg.DescriptionLengthMode {
+	case "", models.LengthBytes, models.LengthVisibleRunes:
+	default:
+		return invalidRuleConfig(fmt.Sprintf("unknown descriptionLengthMode %q", cfg.DescriptionLengthMode))
+	}
+	for name := range cfg.EnabledRules {
+		if !isBuiltinRule(name) {
+			return invalidRuleConfig(fmt.Sprintf("enabledRules: unknown rule %q", name))
+		}
+	}
+	for pattern := range cfg.RetailerAliases {
+		if _, err := aliasPattern(pattern); err != nil {
+			return invalidRuleConfig(fmt.Sprintf("retailerAliases: invalid pattern %q", pattern))
+		}
+	}
+	for i, w := range cfg.TimeWindows {
+		start, err := time.Parse(timeLayout, w.Start)
+		if err != nil {
+			return invalidRuleConfig(fmt.Sprintf("timeWindows[%d]: start must use HH:MM", i))
+		}
+		end, err := time.Parse(timeLayout, w.End)
+		if err != nil {
+			return invalidRuleConfig(fmt.Sprintf("timeWindows[%d]: end must use HH:MM", i))
+		}
+		if start.Equal(end) {
+			return invalidRuleConfig(fmt.Sprintf("timeWindows[%d]: start and end must differ", i))
+		}
+	}
+	return nil
+}
+
+// isBuiltinRule reports whether name is one of the built-in rules.
+func isBuiltinRule(name string) bool {
+	for _, r := range builtinRules {
+		if r.name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func invalidRuleConfig(msg string) error {
+	return fmt.Errorf("invalid rule config: %s", msg)
+}
@@ -0,0 +1,111 @@
+package services
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"receipt-processor/models"
+)
+
+func TestLoadRuleConfigFillsDefaults(t *testing.T) {
+	cfg, err := LoadRuleConfig(strings.NewReader(`{
+		"version": "summer-2024",
+		"oddDayPoints": 12,
+		"timeWindows": [{"start": "09:00", "end": "10:00", "points": 4}]
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := models.DefaultRuleConfig()
+	want.Version = "summer-2024"
+	want.OddDayPoints = 12
+	want.TimeWindows = []models.TimeWindow{{Start: "09:00", End: "10:00", Points: 4}}
+	if !reflect.DeepEqual(cfg, want) {
+		t.Errorf("config = %+v, want %+v", cfg, want)
+	}
+}
+
+func TestLoadRuleConfigKeepsDefaultWindowsWhenOmitted(t *testing.T) {
+	cfg, err := LoadRuleConfig(strings.NewReader(`{"roundDollarPoints": 100}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := models.DefaultRuleConfig()
+	want.RoundDollarPoints = 100
+	if !reflect.DeepEqual(cfg, want) {
+		t.Errorf("config = %+v, want %+v", cfg, want)
+	}
+}
+
+func TestLoadRuleConfigRejectsInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		doc  string
+	}{
+		{"zero group size", `{"itemsPerGroup": 0}`},
+		{"negative length multiple", `{"descriptionLengthMultiple": -3}`},
+		{"unknown rounding mode", `{"roundingMode": "sideways"}`},
+		{"bad window time", `{"timeWindows": [{"start": "2pm", "end": "16:00"}]}`},
+		{"empty window", `{"timeWindows": [{"start": "14:00", "end": "14:00"}]}`},
+		{"grace percent over 100", `{"timeWindowGracePercent": 150}`},
+		{"bad alias pattern", `{"retailerAliases": {"Target (": "Target"}}`},
+		{"unknown rule", `{"enabledRules": {"bonus_retailer": false}}`},
+		{"unknown field", `{"oddDayPoint": 6}`},
+		{"malformed", `{"oddDayPoints": "six"}`},
+	}
+	for _, tt := range tests {
+		if _, err := LoadRuleConfig(strings.NewReader(tt.doc)); err == nil {
+			t.Errorf("%s: expected error", tt.name)
+		}
+	}
+}
+
+func TestLoadRuleConfigReadsUnversionedAsSchemaV1(t *testing.T) {
+	cfg, err := LoadRuleConfig(strings.NewReader(`{
+		"version": "legacy",
+		"oddDayPoints": 8,
+		"timeWindows": [{"start": "11:00", "end": "13:00", "points": 7}]
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := models.DefaultRuleConfig()
+	want.SchemaVersion = 1
+	want.Version = "legacy"
+	want.OddDayPoints = 8
+	want.TimeWindows = []models.TimeWindow{{Start: "11:00", End: "13:00", Points: 7}}
+	if !reflect.DeepEqual(cfg, want) {
+		t.Errorf("config = %+v, want %+v", cfg, want)
+	}
+}
+
+func TestLoadRuleConfigRejectsFutureSchema(t *testing.T) {
+	_, err := LoadRuleConfig(strings.NewReader(`{"schemaVersion": 999, "oddDayPoints": 6}`))
+	if err == nil || !strings.Contains(err.Error(), "unsupported rule config schema version 999") {
+		t.Errorf("err = %v, want an unsupported schema version error", err)
+	}
+}
+
+func TestEncodeRuleConfigRoundTrip(t *testing.T) {
+	cfg := models.DefaultRuleConfig()
+	cfg.SchemaVersion = 0
+	cfg.BasePoints = 5
+	cfg.TimeWindows = append(cfg.TimeWindows, models.TimeWindow{Start: "22:00", End: "02:00", Points: 3})
+
+	var buf bytes.Buffer
+	if err := EncodeRuleConfig(&buf, cfg); err != nil {
+		t.Fatal(err)
+	}
+	got, err := LoadRuleConfig(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg.SchemaVersion = models.RuleConfigSchemaVersion
+	if !reflect.DeepEqual(got, cfg) {
+		t.Errorf("round trip = %+v, want %+v", got, cfg)
+	}
+}
@@ -0,0 +1,51 @@
+package services
+
+import "receipt-processor/models"
+
+// Rule scores one aspect of a receipt. Rules can be composed with
+// CalculatePointsWithRules to build custom scoring.
+type Rule func(receipt models.Receipt) (int, error)
+
+// The built-in scoring rules, bound to DefaultRuleConfig.
+var (
+	RetailerNameRule      = bindRule(retailerNamePoints, models.DefaultRuleConfig())
+	RoundDollarRule       = bindRule(roundDollarPoints, models.DefaultRuleConfig())
+	QuarterMultipleRule   = bindRule(quarterMultiplePoints, models.DefaultRuleConfig())
+	ItemPairsRule         = bindRule(itemPairPoints, models.DefaultRuleConfig())
+	DescriptionLengthRule = bindRule(descriptionLengthPoints, models.DefaultRuleConfig())
+	OddDayRule            = bindRule(oddDayPoints, models.DefaultRuleConfig())
+	EvenDayRule           = bindRule(evenDayPoints, models.DefaultRuleConfig())
+	TimeWindowRule        = bindRule(timeWindowPoints, models.DefaultRuleConfig())
+)
+
+// RulesFor returns the built-in rules bound to cfg, in scoring order.
+// CalculatePointsWithRules(receipt, RulesFor(cfg)) equals
+// CalculatePointsWithConfig(receipt, cfg) before the final adjustments:
+// adding BasePoints, rounding to RoundFinalTo and capping at MaxPoints.
+func RulesFor(cfg models.RuleConfig) []Rule {
+	rules := make([]Rule, len(builtinRules))
+	for i, r := range builtinRules {
+		rules[i] = bindRule(r.apply, cfg)
+	}
+	return rules
+}
+
+// CalculatePointsWithRules sums the points awarded by each rule. The first
+// rule to return an error aborts scoring.
+func CalculatePointsWithRules(receipt models.Receipt, rules []Rule) (int, error) {
+	points := 0
+	for _, r := range rules {
+		p, err := r(receipt)
+		if err != nil {
+			return 0, err
+		}
+		points += p
+	}
+	return points, nil
+}
+
+func bindRule(score func(models.Receipt, models.RuleConfig) (int, error), cfg models.RuleConfig) Rule {
+	return func(receipt models.Receipt) (int, error) {
+		return score(receipt, cfg)
+	}
+}
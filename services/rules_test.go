@@ -0,0 +1,66 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"receipt-processor/models"
+)
+
+func TestCalculatePointsWithRules(t *testing.T) {
+	// Ten points for any receipt from a superstore.
+	superstore := func(receipt models.Receipt) (int, error) {
+		if strings.Contains(receipt.Retailer, "Super") {
+			return 10, nil
+		}
+		return 0, nil
+	}
+	rules := []Rule{RetailerNameRule, RoundDollarRule, superstore}
+
+	receipt := roundReceipt()
+	receipt.Retailer = "Super Mart"
+	got, err := CalculatePointsWithRules(receipt, rules)
+	if err != nil {
+		t.Fatalf("CalculatePointsWithRules: %v", err)
+	}
+	// 9 retailer characters + 50 round dollar + 10 custom.
+	if got != 69 {
+		t.Errorf("points = %d, want 69", got)
+	}
+}
+
+func TestCalculatePointsWithRulesAbortsOnError(t *testing.T) {
+	errRule := errors.New("rule failed")
+	called := false
+	rules := []Rule{
+		RetailerNameRule,
+		func(models.Receipt) (int, error) { return 0, errRule },
+		func(models.Receipt) (int, error) { called = true; return 1, nil },
+	}
+
+	got, err := CalculatePointsWithRules(targetReceipt(), rules)
+	if !errors.Is(err, errRule) {
+		t.Fatalf("error = %v, want %v", err, errRule)
+	}
+	if got != 0 || called {
+		t.Errorf("points = %d, later rule called = %v; want 0, false", got, called)
+	}
+}
+
+func TestRulesForMatchesCalculatePoints(t *testing.T) {
+	cfg := models.DefaultRuleConfig()
+	for _, receipt := range []models.Receipt{targetReceipt(), roundReceipt()} {
+		want, err := CalculatePointsWithConfig(receipt, cfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := CalculatePointsWithRules(receipt, RulesFor(cfg))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != want {
+			t.Errorf("%s: rules = %d, config = %d", receipt.Retailer, got, want)
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package services
+
+import (
+	"errors"
+	"sort"
+	"strings"
+
+	"receipt-processor/models"
+)
+
+// SimilarityConfig selects the fields two receipts must share to count as
+// near-duplicates. Retailers are compared case-insensitively after trimming;
+// all other fields must match exactly. Item descriptions and prices are never
+// compared, so receipts that differ only in item text still match.
+type SimilarityConfig struct {
+	Retailer     bool
+	PurchaseDate bool
+	PurchaseTime bool
+	Total        bool
+	ItemCount    bool
+}
+
+// DefaultSimilarityConfig matches on retailer, purchase date and total.
+func DefaultSimilarityConfig() SimilarityConfig {
+	return SimilarityConfig{Retailer: true, PurchaseDate: true, Total: true}
+}
+
+// FindNearDuplicates returns the sorted IDs of stored receipts that match r on
+// every field enabled in cfg. r is compared in its normalized form, the
+// form Process stores. An entry stored under r's own ID, in any ID format,
+// is an exact duplicate and is not reported.
+func FindNearDuplicates(store ReceiptStore, r models.Receipt, cfg SimilarityConfig) ([]string, error) {
+	if cfg == (SimilarityConfig{}) {
+		return nil, errors.New("similarity config enables no fields")
+	}
+	r = normalizeReceipt(r)
+	ownIDs, err := receiptIDsInAllFormats(r)
+	if err != nil {
+		return nil, err
+	}
+	var ids []string
+	err = store.Range(func(id string, entry models.StoredReceipt) bool {
+		if !ownIDs[id] && similar(r, entry.Receipt, cfg) {
+			ids = append(ids, id)
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(ids)
+	return ids, nil
+}
+
+func similar(a, b models.Receipt, cfg SimilarityConfig) bool {
+	if cfg.Retailer && !strings.EqualFold(strings.TrimSpace(a.Retailer), strings.TrimSpace(b.Retailer)) {
+		return false
+	}
+	if cfg.PurchaseDate && a.PurchaseDate != b.PurchaseDate {
+		return false
+	}
+	if cfg.PurchaseTime && a.PurchaseTime != b.PurchaseTime {
+		return false
+	}
+	if cfg.Total && a.Total != b.Total {
+		return false
+	}
+	if cfg.ItemCount && len(a.Items) != len(b.Items) {
+		return false
+	}
+	return true
+}
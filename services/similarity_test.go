@@ -0,0 +1,103 @@
+package services
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFindNearDuplicates(t *testing.T) {
+	store := NewMapStore()
+	originalID, err := ProcessReceipt(targetReceipt(), store)
+	if err != nil {
+		t.Fatal(err)
+	}
+	differentDay := targetReceipt()
+	differentDay.PurchaseDate = "2022-01-02"
+	if _, err := ProcessReceipt(differentDay, store); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ProcessReceipt(roundReceipt(), store); err != nil {
+		t.Fatal(err)
+	}
+
+	retyped := targetReceipt()
+	retyped.Retailer = "TARGET"
+	retyped.Items[0].ShortDescription = "Mountain Dew 12 Pack"
+
+	got, err := FindNearDuplicates(store, retyped, DefaultSimilarityConfig())
+	if err != nil {
+		t.Fatalf("FindNearDuplicates: %v", err)
+	}
+	if !reflect.DeepEqual(got, []string{originalID}) {
+		t.Errorf("near duplicates = %v, want [%s]", got, originalID)
+	}
+
+	// Requiring the purchase time too still matches; a different time does not.
+	cfg := DefaultSimilarityConfig()
+	cfg.PurchaseTime = true
+	retyped.PurchaseTime = "13:02"
+	got, err = FindNearDuplicates(store, retyped, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 0 {
+		t.Errorf("near duplicates = %v, want none", got)
+	}
+}
+
+func TestFindNearDuplicatesExcludesItself(t *testing.T) {
+	store := NewMapStore()
+	if _, err := ProcessReceipt(targetReceipt(), store); err != nil {
+		t.Fatal(err)
+	}
+	got, err := FindNearDuplicates(store, targetReceipt(), DefaultSimilarityConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 0 {
+		t.Errorf("near duplicates = %v, want none", got)
+	}
+}
+
+func TestFindNearDuplicatesExcludesItselfInAnyFormat(t *testing.T) {
+	p := NewProcessor(NewMapStore())
+	p.IDFormat = IDFormatBase32
+	if _, err := p.Process(targetReceipt()); err != nil {
+		t.Fatal(err)
+	}
+	got, err := FindNearDuplicates(p.Store, targetReceipt(), DefaultSimilarityConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 0 {
+		t.Errorf("near duplicates = %v, want none", got)
+	}
+}
+
+func TestFindNearDuplicatesNonCanonical(t *testing.T) {
+	store := NewMapStore()
+	originalID, err := ProcessReceipt(targetReceipt(), store)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	padded := targetReceipt()
+	padded.Total = "035.35"
+	if got, err := FindNearDuplicates(store, padded, DefaultSimilarityConfig()); err != nil || len(got) != 0 {
+		t.Errorf("itself with a padded total: near duplicates = %v, %v; want none", got, err)
+	}
+	padded.Items[0].ShortDescription = "Mountain Dew 12 Pack"
+	got, err := FindNearDuplicates(store, padded, DefaultSimilarityConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, []string{originalID}) {
+		t.Errorf("retyped with a padded total: near duplicates = %v, want [%s]", got, originalID)
+	}
+}
+
+func TestFindNearDuplicatesRequiresCriteria(t *testing.T) {
+	if _, err := FindNearDuplicates(NewMapStore(), targetReceipt(), SimilarityConfig{}); err == nil {
+		t.Error("expected error for empty similarity config")
+	}
+}
@@ -0,0 +1,31 @@
+package services
+
+import (
+	"encoding/json"
+
+	"receipt-processor/models"
+)
+
+// EstimateStoreBytes approximates the memory held by store as the total
+// length of each entry's ID plus its JSON encoding. It is meant for
+// capacity planning, not exact accounting.
+func EstimateStoreBytes(store ReceiptStore) (int64, error) {
+	var total int64
+	var marshalErr error
+	err := store.Range(func(id string, entry models.StoredReceipt) bool {
+		b, err := json.Marshal(entry)
+		if err != nil {
+			marshalErr = err
+			return false
+		}
+		total += int64(len(id) + len(b))
+		return true
+	})
+	if err != nil {
+		return 0, err
+	}
+	if marshalErr != nil {
+		return 0, marshalErr
+	}
+	return total, nil
+}
@@ -0,0 +1,35 @@
+package services
+
+import (
+	"encoding/json"
+	"testing"
+
+	"receipt-processor/models"
+)
+
+func TestEstimateStoreBytes(t *testing.T) {
+	store := NewMapStore()
+	if n, err := EstimateStoreBytes(store); err != nil || n != 0 {
+		t.Errorf("empty store: %d, %v", n, err)
+	}
+
+	var marshaled int64
+	for _, r := range []models.Receipt{targetReceipt(), roundReceipt(), mmReceipt()} {
+		id, err := ProcessReceipt(r, store)
+		if err != nil {
+			t.Fatal(err)
+		}
+		entry, _ := store.Get(id)
+		b, _ := json.Marshal(entry)
+		marshaled += int64(len(b))
+	}
+
+	got, err := EstimateStoreBytes(store)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The estimate adds each ID to the marshaled entries: 64 hex digits apiece.
+	if tolerance := int64(3 * 64); got < marshaled || got > marshaled+tolerance {
+		t.Errorf("estimate = %d, want within %d of the %d marshaled bytes", got, tolerance, marshaled)
+	}
+}
@@ -0,0 +1,47 @@
+package services
+
+import "testing"
+
+func TestItemSKUValidation(t *testing.T) {
+	tests := []struct {
+		sku   string
+		valid bool
+	}{
+		{"", true},
+		{"SKU12345", true},
+		{"0123456789012", true},
+		{"SKU-123", false},
+		{"SKU 123", false},
+		{"ÄBC123", false},
+	}
+	for _, tt := range tests {
+		receipt := targetReceipt()
+		receipt.Items[0].SKU = tt.sku
+		err := ValidateReceipt(receipt)
+		if tt.valid {
+			if err != nil {
+				t.Errorf("SKU %q: %v", tt.sku, err)
+			}
+			continue
+		}
+		assertValidationCode(t, err, CodeInvalidFormat)
+	}
+}
+
+func TestItemSKUChangesID(t *testing.T) {
+	a, b := targetReceipt(), targetReceipt()
+	a.Items[0].SKU = "A1"
+	b.Items[0].SKU = "B2"
+	if ComputeReceiptID(a) == ComputeReceiptID(b) {
+		t.Error("receipts differing only by SKU share an ID")
+	}
+	if ComputeReceiptID(targetReceipt()) == ComputeReceiptID(a) {
+		t.Error("adding a SKU did not change the ID")
+	}
+
+	pa, _ := CalculatePoints(a)
+	pb, _ := CalculatePoints(targetReceipt())
+	if pa != pb {
+		t.Errorf("SKU changed the score: %d vs %d", pa, pb)
+	}
+}
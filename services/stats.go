@@ -0,0 +1,43 @@
+package services
+
+import (
+	"sort"
+
+	"receipt-processor/models"
+)
+
+// PointsStats summarizes the stored points of every receipt in store. The
+// points are taken as stored, not recalculated. An empty store yields a
+// zero PointsStats, recognizable by its Count.
+func PointsStats(store ReceiptStore) (models.PointsStats, error) {
+	var points []int
+	err := store.Range(func(_ string, entry models.StoredReceipt) bool {
+		points = append(points, entry.Points)
+		return true
+	})
+	if err != nil {
+		return models.PointsStats{}, err
+	}
+	n := len(points)
+	if n == 0 {
+		return models.PointsStats{}, nil
+	}
+	sort.Ints(points)
+
+	sum := 0
+	for _, p := range points {
+		sum += p
+	}
+	median := float64(points[n/2])
+	if n%2 == 0 {
+		median = float64(points[n/2-1]+points[n/2]) / 2
+	}
+	return models.PointsStats{
+		Count:  n,
+		Min:    points[0],
+		Max:    points[n-1],
+		Mean:   float64(sum) / float64(n),
+		Median: median,
+		P90:    points[(9*n+9)/10-1],
+	}, nil
+}
@@ -0,0 +1,41 @@
+package services
+
+import (
+	"fmt"
+	"testing"
+
+	"receipt-processor/models"
+)
+
+func TestPointsStats(t *testing.T) {
+	store := NewMapStore()
+	for i, points := range []int{28, 105, 109, 10, 0, 50, 75, 20, 90, 13} {
+		store.Set(fmt.Sprintf("r%d", i), models.StoredReceipt{Points: points})
+	}
+
+	got, err := PointsStats(store)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Sorted: 0 10 13 20 28 50 75 90 105 109.
+	want := models.PointsStats{Count: 10, Min: 0, Max: 109, Mean: 50, Median: 39, P90: 105}
+	if got != want {
+		t.Errorf("PointsStats = %+v, want %+v", got, want)
+	}
+
+	store.Set("r10", models.StoredReceipt{Points: 200})
+	got, _ = PointsStats(store)
+	if got.Median != 50 || got.P90 != 109 {
+		t.Errorf("odd count: median %v, p90 %d; want 50, 109", got.Median, got.P90)
+	}
+}
+
+func TestPointsStatsEmpty(t *testing.T) {
+	got, err := PointsStats(NewMapStore())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != (models.PointsStats{}) {
+		t.Errorf("PointsStats of an empty store = %+v, want zero", got)
+	}
+}
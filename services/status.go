@@ -0,0 +1,51 @@
+package services
+
+import (
+	"fmt"
+
+	"receipt-processor/models"
+)
+
+// statusTransitions lists the statuses each status may move to. Rejection
+// is final; a verified receipt can still be rejected on later review.
+var statusTransitions = map[models.ReceiptStatus][]models.ReceiptStatus{
+	models.StatusPending:  {models.StatusVerified, models.StatusRejected},
+	models.StatusVerified: {models.StatusRejected},
+	models.StatusRejected: nil,
+}
+
+// StatusTransitionError reports a status change SetStatus does not allow.
+type StatusTransitionError struct {
+	From, To models.ReceiptStatus
+}
+
+func (e *StatusTransitionError) Error() string {
+	return fmt.Sprintf("cannot change receipt status from %s to %s", e.From, e.To)
+}
+
+// SetStatus moves the stored receipt with the given ID to status, returning
+// a *StatusTransitionError and leaving the entry unchanged if the move is
+// not allowed. Setting the current status again is a no-op.
+func SetStatus(id string, status models.ReceiptStatus, store ReceiptStore) error {
+	if _, ok := statusTransitions[status]; !ok {
+		return fmt.Errorf("unknown receipt status %q", status)
+	}
+	entry, err := store.Get(id)
+	if err != nil {
+		return err
+	}
+	from := entry.Status
+	if from == "" {
+		from = models.StatusPending
+	}
+	if from == status {
+		return nil
+	}
+	for _, to := range statusTransitions[from] {
+		if to == status {
+			entry.Status = status
+			return store.Set(id, entry)
+		}
+	}
+	return &StatusTransitionError{From: from, To: status}
+}
@@ -0,0 +1,72 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"receipt-processor/models"
+)
+
+func TestSetStatusTransitions(t *testing.T) {
+	tests := []struct {
+		path []models.ReceiptStatus
+	}{
+		{[]models.ReceiptStatus{models.StatusVerified}},
+		{[]models.ReceiptStatus{models.StatusRejected}},
+		{[]models.ReceiptStatus{models.StatusVerified, models.StatusRejected}},
+		{[]models.ReceiptStatus{models.StatusPending, models.StatusVerified, models.StatusVerified}},
+	}
+	for _, tt := range tests {
+		store := NewMapStore()
+		id, err := ProcessReceipt(targetReceipt(), store)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, status := range tt.path {
+			if err := SetStatus(id, status, store); err != nil {
+				t.Fatalf("%v: SetStatus(%s): %v", tt.path, status, err)
+			}
+		}
+		entry, _ := store.Get(id)
+		if want := tt.path[len(tt.path)-1]; entry.Status != want {
+			t.Errorf("%v: status = %q, want %q", tt.path, entry.Status, want)
+		}
+		if entry.Points != 28 {
+			t.Errorf("%v: points = %d, want 28", tt.path, entry.Points)
+		}
+	}
+}
+
+func TestSetStatusRejectsInvalidTransition(t *testing.T) {
+	store := NewMapStore()
+	id, err := ProcessReceipt(targetReceipt(), store)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := SetStatus(id, models.StatusRejected, store); err != nil {
+		t.Fatal(err)
+	}
+
+	err = SetStatus(id, models.StatusVerified, store)
+	var transErr *StatusTransitionError
+	if !errors.As(err, &transErr) {
+		t.Fatalf("err = %v, want *StatusTransitionError", err)
+	}
+	if transErr.From != models.StatusRejected || transErr.To != models.StatusVerified {
+		t.Errorf("transition = %s -> %s, want rejected -> verified", transErr.From, transErr.To)
+	}
+	if entry, _ := store.Get(id); entry.Status != models.StatusRejected {
+		t.Errorf("status = %q after a rejected transition, want rejected", entry.Status)
+	}
+}
+
+func TestSetStatusErrors(t *testing.T) {
+	store := NewMapStore()
+	if err := SetStatus("missing", models.StatusVerified, store); !errors.Is(err, ErrNotFound) {
+		t.Errorf("missing ID: err = %v, want ErrNotFound", err)
+	}
+	id, _ := ProcessReceipt(targetReceipt(), store)
+	if err := SetStatus(id, "approved", store); err == nil {
+		t.Error("unknown status accepted")
+	}
+}
@@ -0,0 +1,67 @@
+package services
+
+import (
+	"errors"
+
+	"receipt-processor/models"
+)
+
+// ErrNotFound is returned when no receipt is stored under the requested ID.
+var ErrNotFound = errors.New("receipt not found")
+
+// ReceiptStore is the storage backend for processed receipts, keyed by ID.
+type ReceiptStore interface {
+	// Get returns the entry stored under id, or ErrNotFound.
+	Get(id string) (models.StoredReceipt, error)
+	// Set stores entry under id, replacing any existing entry.
+	Set(id string, entry models.StoredReceipt) error
+	// Has reports whether an entry is stored under id.
+	Has(id string) (bool, error)
+	// Delete removes the entry stored under id. Deleting a missing ID is not an error.
+	Delete(id string) error
+	// Range calls fn for each stored entry until fn returns false.
+	Range(fn func(id string, entry models.StoredReceipt) bool) error
+}
+
+// MapStore is an in-memory ReceiptStore backed by a plain map. It is not
+// safe for concurrent use.
+type MapStore struct {
+	entries map[string]models.StoredReceipt
+}
+
+// NewMapStore returns an empty MapStore.
+func NewMapStore() *MapStore {
+	return &MapStore{entries: make(map[string]models.StoredReceipt)}
+}
+
+func (s *MapStore) Get(id string) (models.StoredReceipt, error) {
+	entry, ok := s.entries[id]
+	if !ok {
+		return models.StoredReceipt{}, ErrNotFound
+	}
+	return entry, nil
+}
+
+func (s *MapStore) Set(id string, entry models.StoredReceipt) error {
+	s.entries[id] = entry
+	return nil
+}
+
+func (s *MapStore) Has(id string) (bool, error) {
+	_, ok := s.entries[id]
+	return ok, nil
+}
+
+func (s *MapStore) Delete(id string) error {
+	delete(s.entries, id)
+	return nil
+}
+
+func (s *MapStore) Range(fn func(id string, entry models.StoredReceipt) bool) error {
+	for id, entry := range s.entries {
+		if !fn(id, entry) {
+			break
+		}
+	}
+	return nil
+}
@@ -0,0 +1,26 @@
+package services
+
+import (
+	"sort"
+
+	"receipt-processor/models"
+)
+
+// ListByTag returns the sorted IDs of stored receipts carrying tag.
+func ListByTag(store ReceiptStore, tag string) ([]string, error) {
+	var ids []string
+	err := store.Range(func(id string, entry models.StoredReceipt) bool {
+		for _, t := range entry.Receipt.Tags {
+			if t == tag {
+				ids = append(ids, id)
+				break
+			}
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(ids)
+	return ids, nil
+}
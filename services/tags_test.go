@@ -0,0 +1,75 @@
+package services
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+
+	"receipt-processor/models"
+)
+
+func TestValidateReceiptTags(t *testing.T) {
+	tests := []struct {
+		name string
+		tags []string
+		code string
+	}{
+		{"no tags", nil, ""},
+		{"valid tags", []string{"online", "loyalty-member"}, ""},
+		{"empty tag", []string{"online", ""}, CodeInvalidTag},
+		{"untrimmed tag", []string{" online"}, CodeInvalidTag},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			receipt := targetReceipt()
+			receipt.Tags = tt.tags
+			assertValidationCode(t, ValidateReceipt(receipt), tt.code)
+		})
+	}
+}
+
+func TestTagsNotHashed(t *testing.T) {
+	tagged := targetReceipt()
+	tagged.Tags = []string{"online"}
+	if ComputeReceiptID(tagged) != ComputeReceiptID(targetReceipt()) {
+		t.Error("tags changed the receipt ID")
+	}
+}
+
+func TestListByTag(t *testing.T) {
+	store := NewMapStore()
+	online := targetReceipt()
+	online.Tags = []string{"online", "loyalty-member"}
+	loyalty := roundReceipt()
+	loyalty.Tags = []string{"loyalty-member"}
+	untagged := targetReceipt()
+	untagged.Retailer = "Walgreens"
+
+	var ids []string
+	for _, r := range []models.Receipt{online, loyalty, untagged} {
+		id, err := ProcessReceipt(r, store)
+		if err != nil {
+			t.Fatalf("ProcessReceipt: %v", err)
+		}
+		ids = append(ids, id)
+	}
+
+	got, err := ListByTag(store, "loyalty-member")
+	if err != nil {
+		t.Fatalf("ListByTag: %v", err)
+	}
+	want := []string{ids[0], ids[1]}
+	sort.Strings(want)
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("loyalty-member = %v, want %v", got, want)
+	}
+
+	got, err = ListByTag(store, "online")
+	if err != nil {
+		t.Fatalf("ListByTag: %v", err)
+	}
+	if !reflect.DeepEqual(got, []string{ids[0]}) {
+		t.Errorf("online = %v, want [%s]", got, ids[0])
+	}
+}
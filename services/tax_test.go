@@ -0,0 +1,119 @@
+package services
+
+import (
+	"testing"
+
+	"receipt-processor/models"
+)
+
+// taxReceipt has three products and a tax line totalling 9.80.
+func taxReceipt() models.Receipt {
+	return models.Receipt{
+		Retailer:     "Target",
+		PurchaseDate: "2022-01-02",
+		PurchaseTime: "13:01",
+		Items: []models.Item{
+			{ShortDescription: "Milk", Price: "3.00"},
+			{ShortDescription: "Bread", Price: "2.50"},
+			{ShortDescription: "Eggs", Price: "3.50"},
+			{ShortDescription: "Sales Tax", Price: "0.80", IsTax: true},
+		},
+		Total: "9.80",
+	}
+}
+
+func TestItemPairsExcludeTaxItems(t *testing.T) {
+	include := models.DefaultRuleConfig()
+	exclude := models.DefaultRuleConfig()
+	exclude.ExcludeTaxItems = true
+
+	for _, tt := range []struct {
+		name string
+		cfg  models.RuleConfig
+		want int
+	}{
+		{"tax counted", include, 10},
+		{"tax excluded", exclude, 5},
+	} {
+		got, err := itemPairPoints(taxReceipt(), tt.cfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != tt.want {
+			t.Errorf("%s: item pair points = %d, want %d", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestCheckItemSumWithTax(t *testing.T) {
+	cfg := models.ValidationConfig{CheckItemSum: true}
+
+	assertValidationCode(t, ValidateReceiptWithConfig(taxReceipt(), cfg), "")
+
+	// A total matching only the products leaves the tax unaccounted for.
+	untaxed := taxReceipt()
+	untaxed.Total = "9.00"
+	assertValidationCode(t, ValidateReceiptWithConfig(untaxed, cfg), CodeSumMismatch)
+
+	if err := ValidateReceipt(untaxed); err != nil {
+		t.Errorf("sum check should be off by default: %v", err)
+	}
+}
+
+func TestScoreAccumulatorExcludesTaxItems(t *testing.T) {
+	cfg := models.DefaultRuleConfig()
+	cfg.ExcludeTaxItems = true
+	receipt := taxReceipt()
+
+	acc := NewScoreAccumulator(cfg)
+	acc.SetRetailer(receipt.Retailer)
+	acc.SetDate(receipt.PurchaseDate)
+	acc.SetTime(receipt.PurchaseTime)
+	acc.SetTotal(receipt.Total)
+	for _, item := range receipt.Items {
+		if err := acc.AddItem(item); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	want, err := CalculatePointsWithConfig(receipt, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := acc.Points(); got != want {
+		t.Errorf("Points() = %d, want %d", got, want)
+	}
+}
+
+func TestTaxFlagChangesID(t *testing.T) {
+	product := taxReceipt()
+	product.Items[3].IsTax = false
+	if ComputeReceiptID(product) == ComputeReceiptID(taxReceipt()) {
+		t.Error("IsTax did not affect the receipt ID")
+	}
+}
+
+func TestCheckItemSumTolerance(t *testing.T) {
+	offByOne := taxReceipt()
+	offByOne.Total = "9.81"
+
+	tests := []struct {
+		name      string
+		tolerance int
+		code      string
+	}{
+		{"exact match required", 0, CodeSumMismatch},
+		{"one cent tolerance", 1, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := models.ValidationConfig{CheckItemSum: true, ToleranceCents: tt.tolerance}
+			assertValidationCode(t, ValidateReceiptWithConfig(offByOne, cfg), tt.code)
+		})
+	}
+
+	offByTwo := taxReceipt()
+	offByTwo.Total = "9.78"
+	cfg := models.ValidationConfig{CheckItemSum: true, ToleranceCents: 1}
+	assertValidationCode(t, ValidateReceiptWithConfig(offByTwo, cfg), CodeSumMismatch)
+}
@@ -0,0 +1,67 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+)
+
+type traceIDKey struct{}
+
+// WithTraceID returns a copy of ctx carrying traceID.
+func WithTraceID(ctx context.Context, traceID string) context.Context {
+	return context.WithValue(ctx, traceIDKey{}, traceID)
+}
+
+// TraceIDFromContext returns the trace ID carried by ctx, or "".
+func TraceIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(traceIDKey{}).(string)
+	return id
+}
+
+// NewTraceID returns a random 128-bit trace ID in hex.
+func NewTraceID() string {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		panic(fmt.Sprintf("reading random trace ID: %v", err))
+	}
+	return hex.EncodeToString(b[:])
+}
+
+// ensureTraceID returns ctx unchanged if it carries a trace ID, or a copy
+// carrying a freshly generated one.
+func ensureTraceID(ctx context.Context) (context.Context, string) {
+	if id := TraceIDFromContext(ctx); id != "" {
+		return ctx, id
+	}
+	id := NewTraceID()
+	return WithTraceID(ctx, id), id
+}
+
+// TraceLogHandler is a slog.Handler that adds a "trace_id" attribute to
+// every record logged with a context carrying a trace ID.
+type TraceLogHandler struct {
+	slog.Handler
+}
+
+// NewTraceLogHandler wraps h with trace ID decoration.
+func NewTraceLogHandler(h slog.Handler) *TraceLogHandler {
+	return &TraceLogHandler{Handler: h}
+}
+
+func (h *TraceLogHandler) Handle(ctx context.Context, r slog.Record) error {
+	if id := TraceIDFromContext(ctx); id != "" {
+		r.AddAttrs(slog.String("trace_id", id))
+	}
+	return h.Handler.Handle(ctx, r)
+}
+
+func (h *TraceLogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &TraceLogHandler{Handler: h.Handler.WithAttrs(attrs)}
+}
+
+func (h *TraceLogHandler) WithGroup(name string) slog.Handler {
+	return &TraceLogHandler{Handler: h.Handler.WithGroup(name)}
+}
@@ -0,0 +1,76 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"receipt-processor/models"
+)
+
+// tracedProcessor returns a Processor whose JSON logs go to buf and whose
+// audit entries are appended to entries.
+func tracedProcessor(buf *bytes.Buffer, entries *[]models.AuditEntry) *Processor {
+	p := NewProcessor(NewMapStore())
+	p.Logger = slog.New(NewTraceLogHandler(slog.NewJSONHandler(buf, nil)))
+	p.Audit = func(ctx context.Context, entry models.AuditEntry) {
+		*entries = append(*entries, entry)
+	}
+	return p
+}
+
+func loggedTraceID(t *testing.T, buf *bytes.Buffer) string {
+	t.Helper()
+	var record map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+		t.Fatalf("decode log record %q: %v", buf.String(), err)
+	}
+	id, _ := record["trace_id"].(string)
+	return id
+}
+
+func TestTraceIDPropagates(t *testing.T) {
+	var buf bytes.Buffer
+	var entries []models.AuditEntry
+	p := tracedProcessor(&buf, &entries)
+
+	ctx := WithTraceID(context.Background(), "trace-123")
+	if _, err := p.ProcessContext(ctx, targetReceipt()); err != nil {
+		t.Fatalf("ProcessContext: %v", err)
+	}
+
+	if len(entries) != 1 || entries[0].TraceID != "trace-123" {
+		t.Fatalf("audit entries = %+v, want one with trace-123", entries)
+	}
+	if got := loggedTraceID(t, &buf); got != "trace-123" {
+		t.Errorf("logged trace_id = %q, want trace-123", got)
+	}
+}
+
+func TestTraceIDGeneratedWhenAbsent(t *testing.T) {
+	var buf bytes.Buffer
+	var entries []models.AuditEntry
+	p := tracedProcessor(&buf, &entries)
+
+	receipt := targetReceipt()
+	receipt.Total = "bad"
+	_, err := p.Process(receipt)
+	if len(entries) != 1 || entries[0].TraceID == "" {
+		t.Fatalf("audit entries = %+v, want one with a generated trace ID", entries)
+	}
+	traceID := entries[0].TraceID
+	if got := loggedTraceID(t, &buf); got != traceID {
+		t.Errorf("logged trace_id = %q, audit trace ID = %q", got, traceID)
+	}
+	if err == nil || !strings.Contains(err.Error(), traceID) {
+		t.Errorf("error %v does not mention trace ID %s", err, traceID)
+	}
+	var verr *ValidationError
+	if !errors.As(err, &verr) {
+		t.Errorf("wrapped error lost its *ValidationError: %v", err)
+	}
+}
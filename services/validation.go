@@ -0,0 +1,404 @@
+package services
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"receipt-processor/models"
+)
+
+// Validation error codes reported in ValidationError.Code.
+const (
+	CodeMissingField  = "missing_field"
+	CodeInvalidFormat = "invalid_format"
+	CodeNoItems       = "no_items"
+	CodeInvalidURL    = "invalid_url"
+	CodeInvalidTag    = "invalid_tag"
+	CodeClosed        = "outside_business_hours"
+	CodeInvalidID     = "invalid_id"
+	CodeSumMismatch   = "sum_mismatch"
+	CodeUnsortedItems = "unsorted_items"
+	CodeInvalidNotes  = "invalid_notes"
+	CodeTooLong       = "too_long"
+	CodeBlocked       = "blocked_retailer"
+	CodeTooManyItems  = "too_many_items"
+	CodeDuplicateItem = "duplicate_item"
+	CodeTooOld        = "too_old"
+	CodeMixedDecimals = "inconsistent_decimals"
+	CodeDateMismatch  = "date_mismatch"
+)
+
+// MaxNotesLength is the longest Notes value accepted, in characters.
+const MaxNotesLength = 2000
+
+const (
+	retailerPattern    = `^[\w\s\-&]+$`
+	amountPattern      = `^\d+\.\d{2}$`
+	relaxedPattern     = `^\d+(\.\d{1,2})?$`
+	descriptionPattern = `^[\w\s\-]+$`
+	skuPattern         = `^[A-Za-z0-9]+$`
+)
+
+// The patterns are compiled once rather than on every validation.
+var (
+	retailerRe    = regexp.MustCompile(retailerPattern)
+	amountRe      = regexp.MustCompile(amountPattern)
+	relaxedRe     = regexp.MustCompile(relaxedPattern)
+	descriptionRe = regexp.MustCompile(descriptionPattern)
+	skuRe         = regexp.MustCompile(skuPattern)
+)
+
+// ValidationError describes why a receipt was rejected.
+type ValidationError struct {
+	Code    string
+	Message string
+}
+
+func (e *ValidationError) Error() string {
+	return e.Message
+}
+
+func invalid(code, msg string) *ValidationError {
+	return &ValidationError{Code: code, Message: msg}
+}
+
+// ValidateReceipt validates a receipt under DefaultValidationConfig.
+func ValidateReceipt(receipt models.Receipt) error {
+	return ValidateReceiptWithConfig(receipt, models.DefaultValidationConfig())
+}
+
+// ValidateReceiptWithConfig checks that a receipt has every required field in
+// the expected format and passes the optional checks enabled in cfg. It
+// returns a *ValidationError describing the first problem.
+func ValidateReceiptWithConfig(receipt models.Receipt, cfg models.ValidationConfig) error {
+	if cfg.TrimTotals {
+		receipt = trimAmounts(receipt)
+	}
+	receipt = NormalizeDecimalSeparator(receipt, cfg.DecimalSeparator)
+	amounts := amountRe
+	if cfg.RelaxedAmounts {
+		amounts = relaxedRe
+	}
+	if isBlank(receipt.Retailer) {
+		return invalid(CodeMissingField, "Retailer is required")
+	}
+	if !retailerRe.MatchString(receipt.Retailer) {
+		return invalid(CodeInvalidFormat, "Retailer contains invalid characters")
+	}
+	if isBlocked(receipt.Retailer, cfg.RetailerBlocklist) {
+		return invalid(CodeBlocked, fmt.Sprintf("Receipts from retailer %q are not accepted", strings.TrimSpace(receipt.Retailer)))
+	}
+	if isBlank(receipt.PurchaseDate) {
+		return invalid(CodeMissingField, "PurchaseDate is required")
+	}
+	if _, err := time.Parse(dateLayout, receipt.PurchaseDate); err != nil {
+		return invalid(CodeInvalidFormat, "PurchaseDate must be in YYYY-MM-DD format")
+	}
+	if isBlank(receipt.PurchaseTime) {
+		return invalid(CodeMissingField, "PurchaseTime is required")
+	}
+	if err := CheckPurchaseTimeDate(receipt); err != nil {
+		return err
+	}
+	if _, err := time.Parse(timeLayout, receipt.PurchaseTime); err != nil {
+		return invalid(CodeInvalidFormat, "PurchaseTime must be in HH:MM format")
+	}
+	if len(receipt.Items) == 0 {
+		return invalid(CodeNoItems, "At least one item is required")
+	}
+	for i, item := range receipt.Items {
+		if isBlank(item.ShortDescription) && !cfg.AllowEmptyDescriptions {
+			return invalid(CodeMissingField, "Item ShortDescription is required")
+		}
+		if cfg.MaxDescriptionLength > 0 && utf8.RuneCountInString(item.ShortDescription) > cfg.MaxDescriptionLength {
+			return invalid(CodeTooLong, fmt.Sprintf("Item %d ShortDescription must be at most %d characters",
+				i, cfg.MaxDescriptionLength))
+		}
+		if item.ShortDescription != "" && !descriptionRe.MatchString(item.ShortDescription) {
+			return invalid(CodeInvalidFormat, "Item ShortDescription contains invalid characters")
+		}
+		if item.Price == "" {
+			return invalid(CodeMissingField, "Item Price is required")
+		}
+		if !amounts.MatchString(item.Price) {
+			return invalid(CodeInvalidFormat, "Item Price must be in 0.00 format")
+		}
+		if item.Discount != "" {
+			if !amounts.MatchString(item.Discount) {
+				return invalid(CodeInvalidFormat, "Item Discount must be in 0.00 format")
+			}
+			price, _ := parseCents(item.Price)
+			if discount, _ := parseCents(item.Discount); discount > price {
+				return invalid(CodeInvalidFormat, fmt.Sprintf("Item %d Discount %s exceeds its Price %s",
+					i, item.Discount, item.Price))
+			}
+		}
+		if item.SKU != "" {
+			if !skuRe.MatchString(item.SKU) {
+				return invalid(CodeInvalidFormat, "Item SKU must be alphanumeric")
+			}
+		}
+	}
+	if isBlank(receipt.Total) {
+		return invalid(CodeMissingField, "Total is required")
+	}
+	if !amounts.MatchString(receipt.Total) {
+		return invalid(CodeInvalidFormat, "Total must be in 0.00 format")
+	}
+	if err := checkSubtotalAndTax(receipt, amounts); err != nil {
+		return err
+	}
+	if cfg.RelaxedAmounts && cfg.ConsistentDecimals {
+		if err := checkConsistentDecimals(receipt); err != nil {
+			return err
+		}
+	}
+	if receipt.ImageURL != "" && !isHTTPURL(receipt.ImageURL) {
+		return invalid(CodeInvalidURL, "ImageURL must be an http or https URL")
+	}
+	for _, tag := range receipt.Tags {
+		if tag == "" || strings.TrimSpace(tag) != tag {
+			return invalid(CodeInvalidTag, "Tags must be non-empty and have no surrounding whitespace")
+		}
+	}
+	if !utf8.ValidString(receipt.Notes) {
+		return invalid(CodeInvalidNotes, "Notes must be valid UTF-8")
+	}
+	if utf8.RuneCountInString(receipt.Notes) > MaxNotesLength {
+		return invalid(CodeInvalidNotes, fmt.Sprintf("Notes must be at most %d characters", MaxNotesLength))
+	}
+	if cfg.CheckItemSum {
+		if err := checkItemSum(receipt, cfg.ToleranceCents); err != nil {
+			return err
+		}
+	}
+	if cfg.MaxDistinctItems > 0 {
+		if n := countDistinctItems(receipt.Items); n > cfg.MaxDistinctItems {
+			return invalid(CodeTooManyItems, fmt.Sprintf("Receipt lists %d distinct items, more than the %d allowed",
+				n, cfg.MaxDistinctItems))
+		}
+	}
+	if cfg.RejectDuplicateItems {
+		if err := checkDuplicateItems(receipt.Items); err != nil {
+			return err
+		}
+	}
+	if cfg.RequireSortedItems {
+		if err := checkSortedItems(receipt.Items); err != nil {
+			return err
+		}
+	}
+	if cfg.MaxReceiptAge > 0 {
+		if err := checkReceiptAge(receipt, cfg); err != nil {
+			return err
+		}
+	}
+	if len(cfg.BusinessHours) > 0 {
+		if err := checkBusinessHours(receipt, cfg.BusinessHours); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CheckPurchaseTimeDate rejects a PurchaseTime that carries its own date,
+// such as "2022-01-01T13:01" or "2022-01-01 13:01", when that date differs
+// from PurchaseDate. Times without a date always pass. ValidateReceipt runs
+// it before requiring HH:MM, so a conflict is reported as such; callers
+// accepting other time formats can use it on its own.
+func CheckPurchaseTimeDate(receipt models.Receipt) error {
+	date, ok := embeddedDate(strings.TrimSpace(receipt.PurchaseTime))
+	if !ok || date == receipt.PurchaseDate {
+		return nil
+	}
+	return invalid(CodeDateMismatch, fmt.Sprintf("PurchaseTime date %s does not match PurchaseDate %s",
+		date, receipt.PurchaseDate))
+}
+
+// embeddedDate returns the YYYY-MM-DD date leading a date-time string.
+func embeddedDate(s string) (string, bool) {
+	n := len(dateLayout)
+	if len(s) <= n || (s[n] != 'T' && s[n] != ' ') {
+		return "", false
+	}
+	if _, err := time.Parse(dateLayout, s[:n]); err != nil {
+		return "", false
+	}
+	return s[:n], true
+}
+
+// checkConsistentDecimals checks that every item price has as many decimal
+// places as the total.
+func checkConsistentDecimals(receipt models.Receipt) error {
+	want := decimalPlaces(receipt.Total)
+	for i, item := range receipt.Items {
+		if got := decimalPlaces(item.Price); got != want {
+			return invalid(CodeMixedDecimals, fmt.Sprintf("Item %d Price %s has %d decimal places but Total %s has %d",
+				i, item.Price, got, receipt.Total, want))
+		}
+	}
+	return nil
+}
+
+// decimalPlaces returns the number of digits after the decimal point.
+func decimalPlaces(amount string) int {
+	_, frac, _ := strings.Cut(amount, ".")
+	return len(frac)
+}
+
+// checkSubtotalAndTax validates the optional Subtotal and Tax amounts against
+// amounts and, when both are given, that they add up to Total.
+func checkSubtotalAndTax(receipt models.Receipt, amounts *regexp.Regexp) error {
+	if receipt.Subtotal != "" {
+		if !amounts.MatchString(receipt.Subtotal) {
+			return invalid(CodeInvalidFormat, "Subtotal must be in 0.00 format")
+		}
+	}
+	if receipt.Tax != "" {
+		if !amounts.MatchString(receipt.Tax) {
+			return invalid(CodeInvalidFormat, "Tax must be in 0.00 format")
+		}
+	}
+	if receipt.Subtotal == "" || receipt.Tax == "" {
+		return nil
+	}
+	subtotal, _ := parseCents(receipt.Subtotal)
+	tax, _ := parseCents(receipt.Tax)
+	total, _ := parseCents(receipt.Total)
+	if subtotal+tax != total {
+		return invalid(CodeSumMismatch, fmt.Sprintf("Subtotal %s plus Tax %s does not equal Total %s",
+			receipt.Subtotal, receipt.Tax, receipt.Total))
+	}
+	return nil
+}
+
+// checkItemSum requires product and tax lines together to add up to the
+// total, within toleranceCents.
+func checkItemSum(receipt models.Receipt, toleranceCents int) error {
+	var products, tax int64
+	for _, item := range receipt.Items {
+		cents, _ := effectivePrice(item, ".")
+		if item.IsTax {
+			tax += cents
+		} else {
+			products += cents
+		}
+	}
+	total, _ := parseCents(receipt.Total)
+	diff := products + tax - total
+	if diff < 0 {
+		diff = -diff
+	}
+	if diff > int64(toleranceCents) {
+		return invalid(CodeSumMismatch, fmt.Sprintf("Items sum to %s plus %s tax, but Total is %s",
+			formatCents(products), formatCents(tax), receipt.Total))
+	}
+	return nil
+}
+
+// countDistinctItems counts items by trimmed description and price.
+func countDistinctItems(items []models.Item) int {
+	type key struct{ description, price string }
+	seen := make(map[key]bool, len(items))
+	for _, item := range items {
+		seen[key{strings.TrimSpace(item.ShortDescription), item.Price}] = true
+	}
+	return len(seen)
+}
+
+// checkDuplicateItems rejects the first item that exactly repeats an
+// earlier one.
+func checkDuplicateItems(items []models.Item) error {
+	seen := make(map[models.Item]bool, len(items))
+	for _, item := range items {
+		if seen[item] {
+			return invalid(CodeDuplicateItem, fmt.Sprintf("Item %q appears more than once", item.ShortDescription))
+		}
+		seen[item] = true
+	}
+	return nil
+}
+
+// checkSortedItems requires item prices to be non-decreasing, naming the
+// first item that is cheaper than its predecessor.
+func checkSortedItems(items []models.Item) error {
+	var prev int64
+	for i, item := range items {
+		cents, _ := parseCents(item.Price)
+		if i > 0 && cents < prev {
+			return invalid(CodeUnsortedItems, fmt.Sprintf(
+				"Items must be sorted by price: item %d (%s) is cheaper than item %d", i, item.Price, i-1))
+		}
+		prev = cents
+	}
+	return nil
+}
+
+// checkReceiptAge rejects receipts purchased more than cfg.MaxReceiptAge ago.
+func checkReceiptAge(receipt models.Receipt, cfg models.ValidationConfig) error {
+	now := time.Now
+	if cfg.Now != nil {
+		now = cfg.Now
+	}
+	purchased, err := purchaseMoment(receipt)
+	if err != nil {
+		return err
+	}
+	if now().Sub(purchased) > cfg.MaxReceiptAge {
+		return invalid(CodeTooOld, "receipt too old")
+	}
+	return nil
+}
+
+// checkBusinessHours rejects purchases made while the store was closed.
+func checkBusinessHours(receipt models.Receipt, hours map[time.Weekday]models.OpeningHours) error {
+	date, _ := time.Parse(dateLayout, receipt.PurchaseDate)
+	purchase, _ := time.Parse(timeLayout, receipt.PurchaseTime)
+	day, ok := hours[date.Weekday()]
+	if !ok {
+		return invalid(CodeClosed, "purchase time outside business hours")
+	}
+	open, err := time.Parse(timeLayout, day.Open)
+	if err != nil {
+		return fmt.Errorf("invalid business hours for %s: %w", date.Weekday(), err)
+	}
+	closing, err := time.Parse(timeLayout, day.Close)
+	if err != nil {
+		return fmt.Errorf("invalid business hours for %s: %w", date.Weekday(), err)
+	}
+	if purchase.Before(open) || !purchase.Before(closing) {
+		return invalid(CodeClosed, "purchase time outside business hours")
+	}
+	return nil
+}
+
+// isBlocked reports whether retailer matches a blocklist entry, ignoring
+// case and surrounding whitespace.
+func isBlocked(retailer string, blocklist []string) bool {
+	retailer = strings.TrimSpace(retailer)
+	for _, blocked := range blocklist {
+		if strings.EqualFold(retailer, strings.TrimSpace(blocked)) {
+			return true
+		}
+	}
+	return false
+}
+
+// isBlank reports whether a required field is empty or only whitespace. It
+// is used for presence checks only; values are never trimmed in place.
+func isBlank(s string) bool {
+	return strings.TrimSpace(s) == ""
+}
+
+// isHTTPURL reports whether s is an absolute http or https URL with a host.
+func isHTTPURL(s string) bool {
+	u, err := url.Parse(s)
+	if err != nil {
+		return false
+	}
+	return (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
@@ -0,0 +1,631 @@
+package services
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"receipt-processor/models"
+)
+
+func TestValidateReceipt(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(r *models.Receipt)
+		code   string
+	}{
+		{"valid", func(r *models.Receipt) {}, ""},
+		{"missing retailer", func(r *models.Receipt) { r.Retailer = "" }, CodeMissingField},
+		{"bad date", func(r *models.Receipt) { r.PurchaseDate = "01/02/2022" }, CodeInvalidFormat},
+		{"bad time", func(r *models.Receipt) { r.PurchaseTime = "1pm" }, CodeInvalidFormat},
+		{"no items", func(r *models.Receipt) { r.Items = nil }, CodeNoItems},
+		{"bad price", func(r *models.Receipt) { r.Items[0].Price = "6.4" }, CodeInvalidFormat},
+		{"bad total", func(r *models.Receipt) { r.Total = "35" }, CodeInvalidFormat},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			receipt := targetReceipt()
+			tt.modify(&receipt)
+			assertValidationCode(t, ValidateReceipt(receipt), tt.code)
+		})
+	}
+}
+
+func TestValidateReceiptImageURL(t *testing.T) {
+	tests := []struct {
+		name string
+		url  string
+		code string
+	}{
+		{"valid https", "https://example.com/receipts/123.png", ""},
+		{"valid http", "http://example.com/r.jpg", ""},
+		{"absent", "", ""},
+		{"invalid scheme", "ftp://example.com/r.png", CodeInvalidURL},
+		{"relative", "/receipts/123.png", CodeInvalidURL},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			receipt := targetReceipt()
+			receipt.ImageURL = tt.url
+			assertValidationCode(t, ValidateReceipt(receipt), tt.code)
+		})
+	}
+}
+
+func TestImageURLStoredButNotHashed(t *testing.T) {
+	plain := targetReceipt()
+	withImage := targetReceipt()
+	withImage.ImageURL = "https://example.com/receipts/123.png"
+
+	if ComputeReceiptID(plain) != ComputeReceiptID(withImage) {
+		t.Error("ImageURL changed the receipt ID")
+	}
+
+	store := NewMapStore()
+	id, err := ProcessReceipt(withImage, store)
+	if err != nil {
+		t.Fatalf("ProcessReceipt: %v", err)
+	}
+	entry, err := store.Get(id)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if entry.Receipt.ImageURL != withImage.ImageURL {
+		t.Errorf("stored ImageURL = %q, want %q", entry.Receipt.ImageURL, withImage.ImageURL)
+	}
+	if entry.Points != 28 {
+		t.Errorf("stored points = %d, want 28", entry.Points)
+	}
+}
+
+// assertValidationCode fails unless err is nil when code is empty, or a
+// *ValidationError carrying code otherwise.
+func assertValidationCode(t *testing.T, err error, code string) {
+	t.Helper()
+	if code == "" {
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+		return
+	}
+	var verr *ValidationError
+	if !errors.As(err, &verr) {
+		t.Fatalf("error = %v, want *ValidationError with code %q", err, code)
+	}
+	if verr.Code != code {
+		t.Errorf("code = %q, want %q (%s)", verr.Code, code, verr.Message)
+	}
+}
+
+func TestValidateReceiptWhitespaceOnlyRequiredFields(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(r *models.Receipt)
+		msg    string
+	}{
+		{"retailer", func(r *models.Receipt) { r.Retailer = "   " }, "Retailer is required"},
+		{"total", func(r *models.Receipt) { r.Total = " \t" }, "Total is required"},
+		{"date", func(r *models.Receipt) { r.PurchaseDate = " " }, "PurchaseDate is required"},
+		{"time", func(r *models.Receipt) { r.PurchaseTime = " " }, "PurchaseTime is required"},
+		{"description", func(r *models.Receipt) { r.Items[0].ShortDescription = "  " }, "Item ShortDescription is required"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			receipt := targetReceipt()
+			tt.modify(&receipt)
+			err := ValidateReceipt(receipt)
+			assertValidationCode(t, err, CodeMissingField)
+			if err != nil && err.Error() != tt.msg {
+				t.Errorf("message = %q, want %q", err.Error(), tt.msg)
+			}
+		})
+	}
+}
+
+func TestValidateReceiptKeepsInnerWhitespace(t *testing.T) {
+	receipt := targetReceipt()
+	receipt.Retailer = "  M&M Corner Market "
+	if err := ValidateReceipt(receipt); err != nil {
+		t.Errorf("padded retailer rejected: %v", err)
+	}
+}
+
+func TestValidateMaxDescriptionLength(t *testing.T) {
+	cfg := models.DefaultValidationConfig()
+	limit := cfg.MaxDescriptionLength
+
+	tests := []struct {
+		name   string
+		length int
+		code   string
+	}{
+		{"at the limit", limit, ""},
+		{"one over", limit + 1, CodeTooLong},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			receipt := targetReceipt()
+			receipt.Items[2].ShortDescription = strings.Repeat("a", tt.length)
+			err := ValidateReceiptWithConfig(receipt, cfg)
+			assertValidationCode(t, err, tt.code)
+			if err != nil && !strings.Contains(err.Error(), "Item 2 ") {
+				t.Errorf("error %q should name item 2", err)
+			}
+		})
+	}
+
+	receipt := targetReceipt()
+	receipt.Items[0].ShortDescription = strings.Repeat("a", limit+1)
+	if err := ValidateReceiptWithConfig(receipt, models.ValidationConfig{}); err != nil {
+		t.Errorf("zero limit should disable the check: %v", err)
+	}
+}
+
+func TestPrecompiledPatternsMatchUncompiled(t *testing.T) {
+	inputs := []string{
+		"", " ", "Target", "M&M Corner Market", "Mountain Dew 12PK", "Klarbrunn 12-PK 12 FL OZ",
+		"Shop!", "café", "35.35", "0.00", "35", "35.3", "35.355", "-1.00", "1,00", " 1.00",
+		"SKU123", "SKU-123", "a\nb",
+	}
+	patterns := []struct {
+		pattern string
+		re      interface{ MatchString(string) bool }
+	}{
+		{retailerPattern, retailerRe},
+		{amountPattern, amountRe},
+		{relaxedPattern, relaxedRe},
+		{descriptionPattern, descriptionRe},
+		{skuPattern, skuRe},
+	}
+	for _, p := range patterns {
+		for _, in := range inputs {
+			want, err := regexp.MatchString(p.pattern, in)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := p.re.MatchString(in); got != want {
+				t.Errorf("%s on %q: precompiled %v, uncompiled %v", p.pattern, in, got, want)
+			}
+		}
+	}
+}
+
+// uncompiledFormatChecks runs the validation pattern checks the way they
+// were done before precompilation, compiling each pattern per match.
+func uncompiledFormatChecks(receipt models.Receipt) bool {
+	ok, _ := regexp.MatchString(retailerPattern, receipt.Retailer)
+	for _, item := range receipt.Items {
+		d, _ := regexp.MatchString(descriptionPattern, item.ShortDescription)
+		p, _ := regexp.MatchString(amountPattern, item.Price)
+		ok = ok && d && p
+	}
+	total, _ := regexp.MatchString(amountPattern, receipt.Total)
+	return ok && total
+}
+
+func BenchmarkValidateReceipt(b *testing.B) {
+	receipt := targetReceipt()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if err := ValidateReceipt(receipt); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkUncompiledFormatChecks(b *testing.B) {
+	receipt := targetReceipt()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if !uncompiledFormatChecks(receipt) {
+			b.Fatal("fixture failed the format checks")
+		}
+	}
+}
+
+func TestValidateBusinessHours(t *testing.T) {
+	weekday := models.OpeningHours{Open: "09:00", Close: "21:00"}
+	cfg := models.ValidationConfig{
+		BusinessHours: map[time.Weekday]models.OpeningHours{
+			time.Monday:    weekday,
+			time.Tuesday:   weekday,
+			time.Wednesday: weekday,
+			time.Thursday:  weekday,
+			time.Friday:    weekday,
+			time.Saturday:  {Open: "10:00", Close: "18:00"},
+		},
+	}
+
+	tests := []struct {
+		name string
+		date string
+		time string
+		code string
+	}{
+		{"within hours", "2022-01-03", "13:01", ""},
+		{"at opening", "2022-01-03", "09:00", ""},
+		{"before opening", "2022-01-03", "08:59", CodeClosed},
+		{"at closing", "2022-01-03", "21:00", CodeClosed},
+		{"saturday hours", "2022-01-01", "17:30", ""},
+		{"closed on sunday", "2022-01-02", "13:01", CodeClosed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			receipt := targetReceipt()
+			receipt.PurchaseDate = tt.date
+			receipt.PurchaseTime = tt.time
+			assertValidationCode(t, ValidateReceiptWithConfig(receipt, cfg), tt.code)
+		})
+	}
+}
+
+func TestValidateBusinessHoursDisabledByDefault(t *testing.T) {
+	receipt := targetReceipt()
+	receipt.PurchaseTime = "03:00"
+	if err := ValidateReceipt(receipt); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestRequireSortedItems(t *testing.T) {
+	cfg := models.ValidationConfig{RequireSortedItems: true}
+	withPrices := func(prices ...string) models.Receipt {
+		receipt := targetReceipt()
+		receipt.Items = nil
+		for _, p := range prices {
+			receipt.Items = append(receipt.Items, models.Item{ShortDescription: "Item", Price: p})
+		}
+		return receipt
+	}
+
+	assertValidationCode(t, ValidateReceiptWithConfig(withPrices("1.00", "2.50", "12.00"), cfg), "")
+	assertValidationCode(t, ValidateReceiptWithConfig(withPrices("1.00", "1.00", "3.00"), cfg), "")
+
+	err := ValidateReceiptWithConfig(withPrices("1.00", "5.00", "4.99", "2.00"), cfg)
+	assertValidationCode(t, err, CodeUnsortedItems)
+	if err != nil && !strings.Contains(err.Error(), "item 2 ") {
+		t.Errorf("error %q should name item 2", err)
+	}
+
+	if err := ValidateReceipt(withPrices("5.00", "1.00")); err != nil {
+		t.Errorf("sorting should not be required by default: %v", err)
+	}
+}
+
+func TestNotesStoredUnchanged(t *testing.T) {
+	receipt := targetReceipt()
+	receipt.Notes = "  Customer paid with two cards.\nRefund pending — café receipt attached. "
+
+	if ComputeReceiptID(receipt) != ComputeReceiptID(targetReceipt()) {
+		t.Error("notes changed the receipt ID")
+	}
+
+	store := NewMapStore()
+	id, err := ProcessReceipt(receipt, store)
+	if err != nil {
+		t.Fatalf("ProcessReceipt: %v", err)
+	}
+	entry, err := store.Get(id)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if entry.Receipt.Notes != receipt.Notes {
+		t.Errorf("notes = %q, want %q", entry.Receipt.Notes, receipt.Notes)
+	}
+	if entry.Points != 28 {
+		t.Errorf("points = %d, want 28", entry.Points)
+	}
+}
+
+func TestValidateNotes(t *testing.T) {
+	tests := []struct {
+		name  string
+		notes string
+		code  string
+	}{
+		{"at max length", strings.Repeat("é", MaxNotesLength), ""},
+		{"too long", strings.Repeat("a", MaxNotesLength+1), CodeInvalidNotes},
+		{"invalid UTF-8", "bad \xff byte", CodeInvalidNotes},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			receipt := targetReceipt()
+			receipt.Notes = tt.notes
+			assertValidationCode(t, ValidateReceipt(receipt), tt.code)
+		})
+	}
+}
+
+func TestValidateSubtotalAndTax(t *testing.T) {
+	tests := []struct {
+		name     string
+		subtotal string
+		tax      string
+		code     string
+	}{
+		{"consistent trio", "32.50", "2.85", ""},
+		{"mismatched trio", "32.50", "2.00", CodeSumMismatch},
+		{"total only", "", "", ""},
+		{"subtotal only", "32.50", "", ""},
+		{"malformed tax", "32.50", "2.8", CodeInvalidFormat},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			receipt := targetReceipt() // total 35.35
+			receipt.Subtotal = tt.subtotal
+			receipt.Tax = tt.tax
+			assertValidationCode(t, ValidateReceipt(receipt), tt.code)
+		})
+	}
+}
+
+func TestSubtotalDoesNotAffectScore(t *testing.T) {
+	receipt := targetReceipt()
+	receipt.Subtotal = "32.50"
+	receipt.Tax = "2.85"
+	got, err := CalculatePoints(receipt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != 28 {
+		t.Errorf("points = %d, want 28", got)
+	}
+}
+
+func TestRetailerBlocklist(t *testing.T) {
+	cfg := models.DefaultValidationConfig()
+	cfg.RetailerBlocklist = []string{"  Shady Deals "}
+
+	for _, retailer := range []string{"Shady Deals", "shady deals", "SHADY DEALS", " Shady Deals  "} {
+		receipt := targetReceipt()
+		receipt.Retailer = retailer
+		assertValidationCode(t, ValidateReceiptWithConfig(receipt, cfg), CodeBlocked)
+	}
+
+	for _, retailer := range []string{"Target", "Shady Deals Outlet"} {
+		receipt := targetReceipt()
+		receipt.Retailer = retailer
+		if err := ValidateReceiptWithConfig(receipt, cfg); err != nil {
+			t.Errorf("%q: %v", retailer, err)
+		}
+	}
+}
+
+func TestRetailerBlocklistEmptyByDefault(t *testing.T) {
+	receipt := targetReceipt()
+	receipt.Retailer = "Shady Deals"
+	if err := ValidateReceipt(receipt); err != nil {
+		t.Errorf("ValidateReceipt: %v", err)
+	}
+}
+
+func TestMaxDistinctItems(t *testing.T) {
+	cfg := models.DefaultValidationConfig()
+	cfg.MaxDistinctItems = 2
+
+	repeats := roundReceipt() // four identical Gatorade lines
+	repeats.Items = append(repeats.Items, models.Item{ShortDescription: " Gatorade ", Price: "2.25"},
+		models.Item{ShortDescription: "Chips", Price: "1.50"})
+	if err := ValidateReceiptWithConfig(repeats, cfg); err != nil {
+		t.Errorf("two distinct items across six lines: %v", err)
+	}
+
+	thirdDistinct := repeats
+	thirdDistinct.Items = append(append([]models.Item(nil), repeats.Items...),
+		models.Item{ShortDescription: "Gatorade", Price: "2.50"})
+	assertValidationCode(t, ValidateReceiptWithConfig(thirdDistinct, cfg), CodeTooManyItems)
+
+	if err := ValidateReceipt(targetReceipt()); err != nil {
+		t.Errorf("default config should not cap distinct items: %v", err)
+	}
+}
+
+func TestRejectDuplicateItems(t *testing.T) {
+	cfg := models.DefaultValidationConfig()
+	cfg.RejectDuplicateItems = true
+
+	err := ValidateReceiptWithConfig(roundReceipt(), cfg)
+	assertValidationCode(t, err, CodeDuplicateItem)
+	if err != nil && !strings.Contains(err.Error(), "Gatorade") {
+		t.Errorf("error %q does not name the duplicated item", err)
+	}
+
+	repriced := targetReceipt()
+	repriced.Items = append(repriced.Items, models.Item{ShortDescription: "Mountain Dew 12PK", Price: "5.99"})
+	if err := ValidateReceiptWithConfig(repriced, cfg); err != nil {
+		t.Errorf("same description at a different price: %v", err)
+	}
+
+	if err := ValidateReceipt(roundReceipt()); err != nil {
+		t.Errorf("duplicates should be allowed by default: %v", err)
+	}
+}
+
+func TestMaxReceiptAge(t *testing.T) {
+	now := time.Date(2022, time.February, 1, 12, 0, 0, 0, time.UTC)
+	cfg := models.DefaultValidationConfig()
+	cfg.MaxReceiptAge = 30 * 24 * time.Hour
+	cfg.Now = FixedClock(now)
+
+	recent := targetReceipt()
+	recent.PurchaseDate = "2022-01-15"
+	if err := ValidateReceiptWithConfig(recent, cfg); err != nil {
+		t.Errorf("recent receipt: %v", err)
+	}
+
+	expired := targetReceipt() // 2022-01-01 13:01, 31 days earlier
+	assertValidationCode(t, ValidateReceiptWithConfig(expired, cfg), CodeTooOld)
+
+	cfg.MaxReceiptAge = 0
+	if err := ValidateReceiptWithConfig(expired, cfg); err != nil {
+		t.Errorf("age check should be disabled by default: %v", err)
+	}
+}
+
+func TestProcessorMaxReceiptAgeUsesClock(t *testing.T) {
+	p := NewProcessor(NewMapStore())
+	p.Clock = FixedClock(time.Date(2022, time.January, 5, 0, 0, 0, 0, time.UTC))
+	p.Validation.MaxReceiptAge = 7 * 24 * time.Hour
+
+	if _, err := p.Process(targetReceipt()); err != nil {
+		t.Errorf("receipt within a week of the processor clock: %v", err)
+	}
+	p.Clock = FixedClock(time.Date(2022, time.April, 1, 0, 0, 0, 0, time.UTC))
+	_, err := p.Process(roundReceipt())
+	assertValidationCode(t, err, CodeTooOld)
+}
+
+func TestRelaxedAmounts(t *testing.T) {
+	receipt := targetReceipt()
+	receipt.Total = "35.4"
+	receipt.Items[0].Price = "6"
+	assertValidationCode(t, ValidateReceipt(receipt), CodeInvalidFormat)
+
+	cfg := models.DefaultValidationConfig()
+	cfg.RelaxedAmounts = true
+	if err := ValidateReceiptWithConfig(receipt, cfg); err != nil {
+		t.Errorf("relaxed amounts rejected: %v", err)
+	}
+}
+
+func TestConsistentDecimals(t *testing.T) {
+	cfg := models.DefaultValidationConfig()
+	cfg.RelaxedAmounts = true
+	cfg.ConsistentDecimals = true
+
+	tests := []struct {
+		name  string
+		edit  func(r *models.Receipt)
+		code  string
+		valid bool
+	}{
+		{"all two decimals", func(r *models.Receipt) {}, "", true},
+		{"all one decimal", func(r *models.Receipt) {
+			r.Total = "35.3"
+			for i := range r.Items {
+				r.Items[i].Price = "1.2"
+			}
+		}, "", true},
+		{"one-decimal price among two", func(r *models.Receipt) { r.Items[2].Price = "1.3" }, CodeMixedDecimals, false},
+		{"whole total", func(r *models.Receipt) { r.Total = "35" }, CodeMixedDecimals, false},
+		// Amounts are scored in cents, so three places fail the format check
+		// before consistency is considered.
+		{"three-decimal price among two", func(r *models.Receipt) { r.Items[2].Price = "1.265" }, CodeInvalidFormat, false},
+	}
+	for _, tt := range tests {
+		receipt := targetReceipt()
+		tt.edit(&receipt)
+		err := ValidateReceiptWithConfig(receipt, cfg)
+		if tt.valid {
+			if err != nil {
+				t.Errorf("%s: %v", tt.name, err)
+			}
+			continue
+		}
+		assertValidationCode(t, err, tt.code)
+	}
+
+	mixed := targetReceipt()
+	mixed.Items[2].Price = "1.3"
+	cfg.ConsistentDecimals = false
+	if err := ValidateReceiptWithConfig(mixed, cfg); err != nil {
+		t.Errorf("mixed decimals rejected without ConsistentDecimals: %v", err)
+	}
+}
+
+func TestAllowEmptyDescriptions(t *testing.T) {
+	for _, description := range []string{"", "   "} {
+		receipt := targetReceipt()
+		receipt.Items[1].ShortDescription = description
+		assertValidationCode(t, ValidateReceipt(receipt), CodeMissingField)
+
+		cfg := models.DefaultValidationConfig()
+		cfg.AllowEmptyDescriptions = true
+		if err := ValidateReceiptWithConfig(receipt, cfg); err != nil {
+			t.Errorf("description %q rejected with AllowEmptyDescriptions: %v", description, err)
+		}
+	}
+}
+
+func TestEmptyDescriptionSkipsDescriptionRule(t *testing.T) {
+	receipt := targetReceipt()
+	receipt.Items[1].ShortDescription = "" // was Emils Cheese Pizza, worth 3
+	got, err := CalculatePoints(receipt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != 25 {
+		t.Errorf("points = %d, want 25", got)
+	}
+}
+
+func paddedReceipt() models.Receipt {
+	receipt := roundReceipt()
+	receipt.Total = " 9.00 "
+	receipt.Items[0].Price = "2.25\t"
+	return receipt
+}
+
+func TestTrimTotals(t *testing.T) {
+	assertValidationCode(t, ValidateReceipt(paddedReceipt()), CodeInvalidFormat)
+
+	cfg := models.DefaultValidationConfig()
+	cfg.TrimTotals = true
+	if err := ValidateReceiptWithConfig(paddedReceipt(), cfg); err != nil {
+		t.Errorf("padded amounts rejected with TrimTotals: %v", err)
+	}
+}
+
+func TestProcessTrimsTotals(t *testing.T) {
+	p := NewProcessor(NewMapStore())
+	p.Validation.TrimTotals = true
+	id, err := p.Process(paddedReceipt())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id != ComputeReceiptID(roundReceipt()) {
+		t.Error("padded receipt got a different ID from its trimmed form")
+	}
+	entry, err := p.Store.Get(id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if entry.Receipt.Total != "9.00" || entry.Points != 105 {
+		t.Errorf("stored total %q with %d points, want \"9.00\" with 105", entry.Receipt.Total, entry.Points)
+	}
+}
+
+func TestCheckPurchaseTimeDate(t *testing.T) {
+	tests := []struct {
+		time string
+		code string
+	}{
+		{"13:01", ""},
+		{"2022-01-01T13:01", ""},
+		{"2022-01-01 13:01:00", ""},
+		{"2022-01-02T13:01", CodeDateMismatch},
+		{"2021-12-31 23:59", CodeDateMismatch},
+	}
+	for _, tt := range tests {
+		receipt := targetReceipt() // purchased 2022-01-01
+		receipt.PurchaseTime = tt.time
+		assertValidationCode(t, CheckPurchaseTimeDate(receipt), tt.code)
+	}
+}
+
+func TestValidateReceiptRejectsConflictingTimeDate(t *testing.T) {
+	receipt := targetReceipt()
+	receipt.PurchaseTime = "2022-01-02T13:01"
+	assertValidationCode(t, ValidateReceipt(receipt), CodeDateMismatch)
+
+	// A consistent embedded date still fails the HH:MM format.
+	receipt.PurchaseTime = "2022-01-01T13:01"
+	assertValidationCode(t, ValidateReceipt(receipt), CodeInvalidFormat)
+}
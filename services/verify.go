@@ -0,0 +1,61 @@
+package services
+
+import (
+	"sort"
+
+	"receipt-processor/models"
+)
+
+// VerifyStore recomputes the ID of every stored receipt and returns, sorted,
+// the keys that match none of the ID formats under CurrentIDScheme. Such
+// entries are corrupt or were stored under an older scheme; see MigrateIDs.
+func VerifyStore(store ReceiptStore) ([]string, error) {
+	var mismatched []string
+	var computeErr error
+	err := store.Range(func(id string, entry models.StoredReceipt) bool {
+		ok, err := keyMatchesReceipt(id, entry.Receipt)
+		if err != nil {
+			computeErr = err
+			return false
+		}
+		if !ok {
+			mismatched = append(mismatched, id)
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	if computeErr != nil {
+		return nil, computeErr
+	}
+	sort.Strings(mismatched)
+	return mismatched, nil
+}
+
+// keyMatchesReceipt reports whether id is receipt's ID in any format.
+func keyMatchesReceipt(id string, receipt models.Receipt) (bool, error) {
+	ids, err := receiptIDsInAllFormats(receipt)
+	if err != nil {
+		return false, err
+	}
+	return ids[id], nil
+}
+
+// receiptIDsInAllFormats returns the set of receipt's IDs under
+// CurrentIDScheme, one per ID format.
+func receiptIDsInAllFormats(receipt models.Receipt) (map[string]bool, error) {
+	sum, err := receiptHash(receipt, CurrentIDScheme)
+	if err != nil {
+		return nil, err
+	}
+	ids := make(map[string]bool, len(idFormatPatterns))
+	for format := range idFormatPatterns {
+		id, err := formatID(sum, format)
+		if err != nil {
+			return nil, err
+		}
+		ids[id] = true
+	}
+	return ids, nil
+}
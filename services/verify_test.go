@@ -0,0 +1,41 @@
+package services
+
+import (
+	"reflect"
+	"testing"
+
+	"receipt-processor/models"
+)
+
+func TestVerifyStore(t *testing.T) {
+	store := NewMapStore()
+	if _, err := ProcessReceipt(targetReceipt(), store); err != nil {
+		t.Fatal(err)
+	}
+	p := NewProcessor(store)
+	p.IDFormat = IDFormatUUID
+	if _, err := p.Process(mmReceipt()); err != nil {
+		t.Fatal(err)
+	}
+	if mismatched, err := VerifyStore(store); err != nil || mismatched != nil {
+		t.Fatalf("consistent store: %v, %v", mismatched, err)
+	}
+
+	store.Set("corrupt-key", models.StoredReceipt{Receipt: roundReceipt(), Points: 105})
+	v1ID, _ := ComputeReceiptIDWithScheme(roundReceipt(), IDSchemeV1)
+	other := roundReceipt()
+	other.Total = "9.25"
+	store.Set(v1ID, models.StoredReceipt{Receipt: other})
+
+	mismatched, err := VerifyStore(store)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{v1ID, "corrupt-key"}
+	if v1ID > "corrupt-key" {
+		want = []string{"corrupt-key", v1ID}
+	}
+	if !reflect.DeepEqual(mismatched, want) {
+		t.Errorf("mismatched = %v, want %v", mismatched, want)
+	}
+}
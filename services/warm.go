@@ -0,0 +1,81 @@
+package services
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"receipt-processor/models"
+)
+
+// maxArchiveLine bounds the size of a single NDJSON receipt in an archive.
+const maxArchiveLine = 1 << 20
+
+// LineError is a problem with one line of an NDJSON archive. Line is 1-based.
+type LineError struct {
+	Line int
+	Err  error
+}
+
+func (e LineError) Error() string {
+	return fmt.Sprintf("line %d: %v", e.Line, e.Err)
+}
+
+// ArchiveError collects the lines WarmStore could not load.
+type ArchiveError struct {
+	Lines []LineError
+}
+
+func (e *ArchiveError) Error() string {
+	msgs := make([]string, len(e.Lines))
+	for i, l := range e.Lines {
+		msgs[i] = l.Error()
+	}
+	return fmt.Sprintf("%d archive line(s) failed: %s", len(e.Lines), strings.Join(msgs, "; "))
+}
+
+// WarmStore populates p.Store from an NDJSON archive of receipts, one per
+// line, processing each like a fresh submission to p. Duplicates of already
+// stored receipts are skipped. Lines that fail to decode or validate are
+// collected into an *ArchiveError; the remaining lines are still loaded.
+func (p *Processor) WarmStore(archive io.Reader) (loaded int, err error) {
+	var failed []LineError
+
+	scanner := bufio.NewScanner(archive)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxArchiveLine)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+		var receipt models.Receipt
+		if err := json.Unmarshal([]byte(text), &receipt); err != nil {
+			failed = append(failed, LineError{Line: line, Err: err})
+			continue
+		}
+		_, err := p.Process(receipt)
+		switch {
+		case errors.Is(err, ErrDuplicateReceipt):
+		case err != nil:
+			failed = append(failed, LineError{Line: line, Err: err})
+		default:
+			loaded++
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return loaded, err
+	}
+	if len(failed) > 0 {
+		return loaded, &ArchiveError{Lines: failed}
+	}
+	return loaded, nil
+}
+
+// WarmStore populates store from an NDJSON archive with the default
+// Processor for store. See Processor.WarmStore.
+func WarmStore(store ReceiptStore, archive io.Reader) (loaded int, err error) {
+	return NewProcessor(store).WarmStore(archive)
+}
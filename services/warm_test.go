@@ -0,0 +1,90 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"receipt-processor/models"
+)
+
+func ndjson(t *testing.T, receipts ...models.Receipt) []string {
+	t.Helper()
+	lines := make([]string, len(receipts))
+	for i, r := range receipts {
+		b, err := json.Marshal(r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		lines[i] = string(b)
+	}
+	return lines
+}
+
+func TestWarmStore(t *testing.T) {
+	invalid := roundReceipt()
+	invalid.Total = "9"
+	lines := ndjson(t, targetReceipt(), roundReceipt(), targetReceipt(), invalid)
+	archive := strings.Join([]string{
+		lines[0],
+		lines[1],
+		"",
+		lines[2],
+		`{"retailer": `,
+		lines[3],
+	}, "\n")
+
+	store := NewMapStore()
+	loaded, err := WarmStore(store, strings.NewReader(archive))
+	if loaded != 2 {
+		t.Errorf("loaded = %d, want 2", loaded)
+	}
+
+	var aerr *ArchiveError
+	if !errors.As(err, &aerr) {
+		t.Fatalf("error = %v, want *ArchiveError", err)
+	}
+	if len(aerr.Lines) != 2 || aerr.Lines[0].Line != 5 || aerr.Lines[1].Line != 6 {
+		t.Errorf("failed lines = %+v, want lines 5 and 6", aerr.Lines)
+	}
+
+	entry, err := store.Get(ComputeReceiptID(targetReceipt()))
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if entry.Points != 28 {
+		t.Errorf("points = %d, want 28", entry.Points)
+	}
+}
+
+func TestWarmStoreClean(t *testing.T) {
+	archive := strings.Join(ndjson(t, targetReceipt(), roundReceipt()), "\n")
+	loaded, err := WarmStore(NewMapStore(), strings.NewReader(archive))
+	if err != nil || loaded != 2 {
+		t.Errorf("WarmStore = (%d, %v), want (2, nil)", loaded, err)
+	}
+}
+
+func TestProcessorWarmStoreUsesProcessorSettings(t *testing.T) {
+	p := NewProcessor(NewMapStore())
+	p.IDFormat = IDFormatUUID
+	p.Rules.OddDayPoints = 20
+
+	archive := strings.Join(ndjson(t, targetReceipt()), "\n")
+	if loaded, err := p.WarmStore(strings.NewReader(archive)); err != nil || loaded != 1 {
+		t.Fatalf("WarmStore = (%d, %v), want (1, nil)", loaded, err)
+	}
+
+	id, err := ComputeReceiptIDWithFormat(targetReceipt(), IDFormatUUID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	entry, err := p.Store.Get(id)
+	if err != nil {
+		t.Fatalf("Get(%s): %v", id, err)
+	}
+	if entry.Points != 42 {
+		t.Errorf("points = %d, want 42", entry.Points)
+	}
+}
@@ -0,0 +1,20 @@
+package services
+
+import "receipt-processor/models"
+
+// ValidateReceiptWithWarnings validates receipt like ValidateReceipt and, if
+// it is valid, also reports conditions that are suspicious but do not
+// prevent acceptance, such as a zero total or a purchase at exactly
+// midnight. Warnings are nil when err is not.
+func ValidateReceiptWithWarnings(receipt models.Receipt) (warnings []string, err error) {
+	if err := ValidateReceipt(receipt); err != nil {
+		return nil, err
+	}
+	if total, _ := parseCents(receipt.Total); total == 0 {
+		warnings = append(warnings, "Total is 0.00")
+	}
+	if receipt.PurchaseTime == "00:00" {
+		warnings = append(warnings, "PurchaseTime is exactly midnight")
+	}
+	return warnings, nil
+}
@@ -0,0 +1,44 @@
+package services
+
+import (
+	"reflect"
+	"testing"
+
+	"receipt-processor/models"
+)
+
+func TestValidateReceiptWithWarnings(t *testing.T) {
+	free := targetReceipt()
+	free.Total = "0.00"
+	midnight := free
+	midnight.PurchaseTime = "00:00"
+
+	tests := []struct {
+		name    string
+		receipt models.Receipt
+		want    []string
+	}{
+		{"normal", targetReceipt(), nil},
+		{"zero total", free, []string{"Total is 0.00"}},
+		{"zero total at midnight", midnight, []string{"Total is 0.00", "PurchaseTime is exactly midnight"}},
+	}
+	for _, tt := range tests {
+		warnings, err := ValidateReceiptWithWarnings(tt.receipt)
+		if err != nil {
+			t.Errorf("%s: unexpected error %v", tt.name, err)
+		}
+		if !reflect.DeepEqual(warnings, tt.want) {
+			t.Errorf("%s: warnings = %q, want %q", tt.name, warnings, tt.want)
+		}
+	}
+}
+
+func TestValidateReceiptWithWarningsMalformed(t *testing.T) {
+	receipt := targetReceipt()
+	receipt.Total = "0"
+	warnings, err := ValidateReceiptWithWarnings(receipt)
+	assertValidationCode(t, err, CodeInvalidFormat)
+	if warnings != nil {
+		t.Errorf("warnings = %q alongside an error, want nil", warnings)
+	}
+}